@@ -0,0 +1,3 @@
+package blockpb
+
+//go:generate protoc header.proto --go_out=.
@@ -1,10 +1,17 @@
 package block
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"io"
+	"math"
 	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -15,7 +22,10 @@ import (
 	v1 "github.com/harmony-one/harmony/block/v1"
 	v2 "github.com/harmony-one/harmony/block/v2"
 	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
 	"github.com/harmony-one/harmony/crypto/hash"
+	common2 "github.com/harmony-one/harmony/internal/common"
+	"github.com/harmony-one/harmony/shard"
 	"github.com/harmony-one/taggedrlp"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -24,115 +34,757 @@ import (
 // Header represents a block header in the Harmony blockchain.
 type Header struct {
 	blockif.Header
-}
 
-// HeaderPair ..
-type HeaderPair struct {
-	BeaconHeader *Header `json:"beacon-chain-header"`
-	ShardHeader  *Header `json:"shard-chain-header"`
+	// cachedHash holds the *common.Hash computed by Hash, or a nil pointer
+	// once a setter has invalidated it.
+	cachedHash atomic.Value
 }
 
 var (
 	// ErrHeaderIsNil ..
 	ErrHeaderIsNil = errors.New("cannot encode nil header receiver")
+	// ErrNoShardState is returned when decoding the shard state of a header
+	// that carries none.
+	ErrNoShardState = errors.New("header has no shard state")
+	// ErrUnknownHeaderVersion is matched (with errors.Is) by the error
+	// returned when decoding a header whose version tag is not registered,
+	// e.g. one produced by a newer node.
+	ErrUnknownHeaderVersion = errors.New("unknown header version")
+	// ErrVersionNotAllowed is returned by DecodeRLPAllowing when the decoded
+	// header version is not in the allowlist.
+	ErrVersionNotAllowed = errors.New("header version not allowed")
+	// ErrNumberUnset is returned by NumberU64 when the block number is not
+	// set.
+	ErrNumberUnset = errors.New("block number is not set")
+	// ErrNumberOverflow is returned by NumberU64 when the block number does
+	// not fit in a uint64.
+	ErrNumberOverflow = errors.New("block number is out of uint64 range")
+	// ErrNotLegacyHeader is returned by DecodeLegacyHeader when given a
+	// tagged header encoding.
+	ErrNotLegacyHeader = errors.New("not a bare legacy (v0) header encoding")
 )
 
+// unknownHeaderVersionError wraps the taggedrlp error for an unregistered
+// header version tag so that it matches ErrUnknownHeaderVersion.  Its cause
+// remains the original taggedrlp.UnsupportedTag.
+type unknownHeaderVersionError struct {
+	cause taggedrlp.UnsupportedTag
+}
+
+func (e unknownHeaderVersionError) Error() string {
+	return ErrUnknownHeaderVersion.Error() + ": " + e.cause.Error()
+}
+
+// Cause returns the original taggedrlp error, for errors.Cause.
+func (e unknownHeaderVersionError) Cause() error { return e.cause }
+
+// Unwrap returns the original taggedrlp error, for errors.As.
+func (e unknownHeaderVersionError) Unwrap() error { return e.cause }
+
+// Is reports whether target is ErrUnknownHeaderVersion, for errors.Is.
+func (e unknownHeaderVersionError) Is(target error) bool {
+	return target == ErrUnknownHeaderVersion
+}
+
+// headerJSON is the JSON representation of a header.
+//
+// Fields that the underlying header version does not carry are nil, and
+// are thus encoded as null.
+type headerJSON struct {
+	ParentHash          common.Hash      `json:"parentHash"`
+	UncleHash           common.Hash      `json:"sha3Uncles"`
+	Nonce               types.BlockNonce `json:"nonce"`
+	Coinbase            common.Address   `json:"miner"`
+	MinerBech32         string           `json:"minerBech32"`
+	Root                common.Hash      `json:"stateRoot"`
+	TxHash              common.Hash      `json:"transactionsRoot"`
+	ReceiptHash         common.Hash      `json:"receiptsRoot"`
+	OutgoingReceiptHash *common.Hash     `json:"outgoingReceiptsRoot"`
+	IncomingReceiptHash *common.Hash     `json:"incomingReceiptsRoot"`
+	Bloom               types.Bloom      `json:"logsBloom"`
+	Difficulty          *hexutil.Big     `json:"difficulty"`
+	Number              *hexutil.Big     `json:"number"`
+	GasLimit            hexutil.Uint64   `json:"gasLimit"`
+	GasUsed             hexutil.Uint64   `json:"gasUsed"`
+	Time                *hexutil.Big     `json:"timestamp"`
+	Extra               hexutil.Bytes    `json:"extraData"`
+	MixDigest           common.Hash      `json:"mixHash"`
+	Hash                common.Hash      `json:"hash"`
+	// Additional Fields
+	ViewID              string         `json:"viewID"`
+	Epoch               string         `json:"epoch"`
+	ShardID             string         `json:"shardID"`
+	LastCommitSignature hexutil.Bytes  `json:"lastCommitSignature"`
+	LastCommitBitmap    hexutil.Bytes  `json:"lastCommitBitmap"`
+	ShardStateRoot      *common.Hash   `json:"shardStateRoot"`
+	ShardStateHash      common.Hash    `json:"shardStateHash"`
+	ShardState          hexutil.Bytes  `json:"shardState"`
+	Vrf                 *hexutil.Bytes `json:"vrf"`
+	Vdf                 *hexutil.Bytes `json:"vdf"`
+	CrossLinks          *hexutil.Bytes `json:"crossLinks"`
+	Slashes             *hexutil.Bytes `json:"slashes"`
+	BaseFee             *hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	Version             string         `json:"version"`
+}
+
+// headerJSONKey returns the JSON object key of the given headerJSON field.
+func headerJSONKey(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
 // MarshalJSON ..
-func (h Header) MarshalJSON() ([]byte, error) {
-	return json.Marshal(struct {
-		ParentHash  common.Hash      `json:"parentHash"`
-		UncleHash   common.Hash      `json:"sha3Uncles"`
-		Nonce       types.BlockNonce `json:"nonce"`
-		Coinbase    common.Address   `json:"miner"`
-		Root        common.Hash      `json:"stateRoot"`
-		TxHash      common.Hash      `json:"transactionsRoot"`
-		ReceiptHash common.Hash      `json:"receiptsRoot"`
-		Bloom       types.Bloom      `json:"logsBloom"`
-		Difficulty  *hexutil.Big     `json:"difficulty"`
-		Number      *hexutil.Big     `json:"number"`
-		GasLimit    hexutil.Uint64   `json:"gasLimit"`
-		GasUsed     hexutil.Uint64   `json:"gasUsed"`
-		Time        *hexutil.Big     `json:"timestamp"`
-		Extra       hexutil.Bytes    `json:"extraData"`
-		MixDigest   common.Hash      `json:"mixHash"`
-		Hash        common.Hash      `json:"hash"`
-		// Additional Fields
-		ViewID  *big.Int `json:"viewID"`
-		Epoch   *big.Int `json:"epoch"`
-		ShardID uint32   `json:"shardID"`
-	}{
-		h.ParentHash(),
-		common.Hash{},
-		types.BlockNonce{},
-		h.Coinbase(),
-		h.Root(),
-		h.TxHash(),
-		h.ReceiptHash(),
-		h.Bloom(),
-		(*hexutil.Big)(big.NewInt(0)),
-		(*hexutil.Big)(h.Number()),
-		hexutil.Uint64(h.GasLimit()),
-		hexutil.Uint64(h.GasUsed()),
-		(*hexutil.Big)(h.Time()),
-		h.Extra(),
-		h.MixDigest(),
-		h.Hash(),
-		h.Header.ViewID(),
-		h.Header.Epoch(),
-		h.Header.ShardID(),
-	})
+//
+// It has a pointer receiver so that marshaling never copies the hash cache.
+func (h *Header) MarshalJSON() ([]byte, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	return marshalHeaderJSON(h, newHeaderJSON(h))
+}
+
+// headerFieldsMarshaler is implemented by header versions that can encode
+// their fields as a JSON object.
+type headerFieldsMarshaler interface {
+	MarshalFieldsJSON() ([]byte, error)
+}
+
+// marshalHeaderJSON returns the JSON encoding of enc, a JSON object holding
+// the wrapper representation of h, followed by the members of the JSON
+// object of the concrete header version that enc lacks.  Thus a field added
+// to a header version appears in the JSON form even before the wrapper
+// representation learns of it, while known fields keep their RPC formats.
+func marshalHeaderJSON(h *Header, enc interface{}) ([]byte, error) {
+	b, err := json.Marshal(enc)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := h.Header.(headerFieldsMarshaler)
+	if !ok {
+		return b, nil
+	}
+	fields, err := m.MarshalFieldsJSON()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode header fields")
+	}
+	var known map[string]json.RawMessage
+	if err := json.Unmarshal(b, &known); err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(fields))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.Errorf("header fields of %T are not a JSON object", h.Header)
+	}
+	out := bytes.NewBuffer(bytes.TrimSuffix(b, []byte("}")))
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse header fields")
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, errors.Wrap(err, "cannot parse header fields")
+		}
+		key := tok.(string)
+		if _, ok := known[key]; ok {
+			continue
+		}
+		keyJSON, _ := json.Marshal(key)
+		if len(known) > 0 {
+			out.WriteByte(',')
+		}
+		known[key] = value
+		out.Write(keyJSON)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
+
+// MarshalJSONWithRaw returns the JSON form of the header, as MarshalJSON, with
+// an additional "raw" field holding the hex-encoded tagged RLP encoding of
+// the header, so that both can be cross-checked from one payload.
+// UnmarshalJSON ignores the raw field.
+func (h *Header) MarshalJSONWithRaw() ([]byte, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	raw, err := h.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	enc := struct {
+		headerJSON
+		Raw hexutil.Bytes `json:"raw"`
+	}{newHeaderJSON(h), raw}
+	return marshalHeaderJSON(h, enc)
+}
+
+// newHeaderJSON returns the JSON representation of the given header.
+func newHeaderJSON(h *Header) headerJSON {
+	v, _ := headerVersionOf(h.Header)
+	lastCommitSignature := h.LastCommitSignature()
+	minerBech32, _ := h.CoinbaseBech32()
+	enc := headerJSON{
+		ParentHash:          h.ParentHash(),
+		Coinbase:            h.Coinbase(),
+		MinerBech32:         minerBech32,
+		Root:                h.Root(),
+		TxHash:              h.TxHash(),
+		ReceiptHash:         h.ReceiptHash(),
+		Bloom:               h.Bloom(),
+		Difficulty:          (*hexutil.Big)(big.NewInt(0)),
+		Number:              (*hexutil.Big)(h.Number()),
+		GasLimit:            hexutil.Uint64(h.GasLimit()),
+		GasUsed:             hexutil.Uint64(h.GasUsed()),
+		Time:                (*hexutil.Big)(h.Time()),
+		Extra:               h.Extra(),
+		MixDigest:           h.MixDigest(),
+		Hash:                h.Hash(),
+		ViewID:              h.Header.ViewID().String(),
+		Epoch:               h.Header.Epoch().String(),
+		ShardID:             strconv.FormatUint(uint64(h.Header.ShardID()), 10),
+		LastCommitSignature: lastCommitSignature[:],
+		LastCommitBitmap:    h.LastCommitBitmap(),
+		ShardStateHash:      h.ShardStateBytesHash(),
+		ShardState:          h.ShardState(),
+		Version:             headerVersionName(h.Header),
+	}
+	if v.features.crossShardReceipts {
+		outgoingReceiptHash := h.OutgoingReceiptHash()
+		incomingReceiptHash := h.IncomingReceiptHash()
+		enc.OutgoingReceiptHash = &outgoingReceiptHash
+		enc.IncomingReceiptHash = &incomingReceiptHash
+	}
+	if v.features.shardStateRoot {
+		shardStateRoot := h.ShardStateHash()
+		enc.ShardStateRoot = &shardStateRoot
+	}
+	if v.features.randomness {
+		vrf, vdf := hexutil.Bytes(h.Vrf()), hexutil.Bytes(h.Vdf())
+		enc.Vrf, enc.Vdf = &vrf, &vdf
+	}
+	if v.features.crossLinks {
+		crossLinks := hexutil.Bytes(h.CrossLinks())
+		enc.CrossLinks = &crossLinks
+	}
+	if v.features.slashes {
+		slashes := hexutil.Bytes(h.Slashes())
+		enc.Slashes = &slashes
+	}
+	if v.features.baseFee {
+		enc.BaseFee = (*hexutil.Big)(h.BaseFee())
+	}
+	return enc
+}
+
+// UnmarshalJSON decodes the JSON form produced by MarshalJSON.
+//
+// The version field selects the concrete header type; a missing version
+// denotes the legacy (v0) header.  Derived or Ethereum-only fields such as
+// sha3Uncles, minerBech32, shardStateHash, and hash are ignored.
+func (h *Header) UnmarshalJSON(data []byte) error {
+	if h == nil {
+		return ErrHeaderIsNil
+	}
+	var dec headerJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	hif, err := dec.header()
+	if err != nil {
+		return err
+	}
+	h.Header = hif
+	h.invalidateHash()
+	return nil
+}
+
+// header returns a new header built from the JSON representation.
+func (dec *headerJSON) header() (blockif.Header, error) {
+	version := dec.Version
+	if version == "" {
+		version = legacyVersionName
+	}
+	v, ok := headerVersionByName(version)
+	if !ok {
+		return nil, errors.Errorf("unknown header version %#v", version)
+	}
+	hif := v.factory()
+	hif.SetParentHash(dec.ParentHash)
+	hif.SetCoinbase(dec.Coinbase)
+	hif.SetRoot(dec.Root)
+	hif.SetTxHash(dec.TxHash)
+	hif.SetReceiptHash(dec.ReceiptHash)
+	if v.features.crossShardReceipts {
+		if dec.OutgoingReceiptHash != nil {
+			hif.SetOutgoingReceiptHash(*dec.OutgoingReceiptHash)
+		}
+		if dec.IncomingReceiptHash != nil {
+			hif.SetIncomingReceiptHash(*dec.IncomingReceiptHash)
+		}
+	}
+	hif.SetBloom(dec.Bloom)
+	if dec.Number != nil {
+		hif.SetNumber((*big.Int)(dec.Number))
+	}
+	hif.SetGasLimit(uint64(dec.GasLimit))
+	hif.SetGasUsed(uint64(dec.GasUsed))
+	if dec.Time != nil {
+		hif.SetTime((*big.Int)(dec.Time))
+	}
+	hif.SetExtra(dec.Extra)
+	hif.SetMixDigest(dec.MixDigest)
+	if dec.ViewID != "" {
+		viewID, ok := new(big.Int).SetString(dec.ViewID, 10)
+		if !ok {
+			return nil, errors.Errorf("invalid viewID %#v", dec.ViewID)
+		}
+		hif.SetViewID(viewID)
+	}
+	if dec.Epoch != "" {
+		epoch, ok := new(big.Int).SetString(dec.Epoch, 10)
+		if !ok {
+			return nil, errors.Errorf("invalid epoch %#v", dec.Epoch)
+		}
+		hif.SetEpoch(epoch)
+	}
+	if dec.ShardID != "" {
+		shardID, err := strconv.ParseUint(dec.ShardID, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid shardID %#v", dec.ShardID)
+		}
+		hif.SetShardID(uint32(shardID))
+	}
+	if len(dec.LastCommitSignature) > 0 {
+		var sig [96]byte
+		if len(dec.LastCommitSignature) != len(sig) {
+			return nil, errors.Errorf("invalid lastCommitSignature length %d",
+				len(dec.LastCommitSignature))
+		}
+		copy(sig[:], dec.LastCommitSignature)
+		hif.SetLastCommitSignature(sig)
+	}
+	hif.SetLastCommitBitmap(dec.LastCommitBitmap)
+	if v.features.shardStateRoot && dec.ShardStateRoot != nil {
+		hif.SetShardStateHash(*dec.ShardStateRoot)
+	}
+	hif.SetShardState(dec.ShardState)
+	if v.features.randomness && dec.Vrf != nil {
+		hif.SetVrf(*dec.Vrf)
+	}
+	if v.features.randomness && dec.Vdf != nil {
+		hif.SetVdf(*dec.Vdf)
+	}
+	if v.features.crossLinks && dec.CrossLinks != nil {
+		hif.SetCrossLinks(*dec.CrossLinks)
+	}
+	if v.features.slashes && dec.Slashes != nil {
+		hif.SetSlashes(*dec.Slashes)
+	}
+	if v.features.baseFee && dec.BaseFee != nil {
+		hif.SetBaseFee((*big.Int)(dec.BaseFee))
+	}
+	return hif, nil
 }
 
 // String ..
-func (h Header) String() string {
+func (h *Header) String() string {
 	s, _ := json.Marshal(h)
 	return string(s)
 }
 
 // EncodeRLP encodes the header using tagged RLP representation.
 func (h *Header) EncodeRLP(w io.Writer) error {
+	return h.EncodeRLPWithRegistry(w, HeaderRegistry)
+}
+
+// EncodeRLPWithRegistry is like EncodeRLP, but uses the given registry
+// instead of HeaderRegistry.
+func (h *Header) EncodeRLPWithRegistry(w io.Writer, reg *taggedrlp.Registry) error {
 	if h == nil {
 		return ErrHeaderIsNil
 	}
-	return HeaderRegistry.Encode(w, h.Header)
+	useHeaderRegistry(reg)
+	return reg.Encode(w, h.Header)
+}
+
+// EncodeRLPInto writes the tagged RLP encoding of the header, the same bytes
+// as returned by Bytes, to w.  The encoding is completed before anything is
+// written, so w receives either the whole encoding or nothing; this makes it
+// suitable for writers that cannot be rewound, such as a running hash.Hash.
+func (h *Header) EncodeRLPInto(w io.Writer) error {
+	b, err := h.Bytes()
+	if err != nil {
+		return err
+	}
+	n, err := w.Write(b)
+	if err == nil && n < len(b) {
+		err = io.ErrShortWrite
+	}
+	return err
 }
 
 // DecodeRLP decodes the header using tagged RLP representation.
 func (h *Header) DecodeRLP(s *rlp.Stream) error {
+	return h.DecodeRLPWithRegistry(s, HeaderRegistry)
+}
+
+// DecodeRLPWithRegistry is like DecodeRLP, but uses the given registry
+// instead of HeaderRegistry.
+//
+// An encoding that cannot be decoded yields a *HeaderDecodeError.  Errors
+// reading the stream itself, such as rlp.EOL at the end of an enclosing list,
+// are returned as is.
+func (h *Header) DecodeRLPWithRegistry(s *rlp.Stream, reg *taggedrlp.Registry) error {
 	if h == nil {
 		return ErrHeaderIsNil
 	}
-	decoded, err := HeaderRegistry.Decode(s)
+	raw, err := s.Raw()
 	if err != nil {
 		return err
 	}
+	useHeaderRegistry(reg)
+	decoded, err := decodeTaggedHeader(raw, reg)
+	if err != nil {
+		if tagErr, ok := err.(taggedrlp.UnsupportedTag); ok {
+			err = unknownHeaderVersionError{tagErr}
+		}
+		return &HeaderDecodeError{Version: encodedHeaderVersion(raw), Err: err}
+	}
 	hif, ok := decoded.(blockif.Header)
 	if !ok {
-		return errors.Errorf(
+		return &HeaderDecodeError{Version: encodedHeaderVersion(raw), Err: errors.Errorf(
 			"decoded object (type %s) does not implement Header interface",
-			taggedrlp.TypeName(reflect.TypeOf(decoded)))
+			taggedrlp.TypeName(reflect.TypeOf(decoded)))}
 	}
+	observeDecode(raw)
 	h.Header = hif
+	h.invalidateHash()
+	return nil
+}
+
+// DecodeRLPAllowing is like DecodeRLP, but only accepts header versions whose
+// names, as returned by Version, are in allowed.  This lets a deployment
+// reject header versions that it has not vetted even though they are
+// registered.  The header is left unchanged if decoding fails.
+//
+// The error for a registered but disallowed version wraps
+// ErrVersionNotAllowed.
+func (h *Header) DecodeRLPAllowing(s *rlp.Stream, allowed []string) error {
+	if h == nil {
+		return ErrHeaderIsNil
+	}
+	var decoded Header
+	if err := decoded.DecodeRLP(s); err != nil {
+		return err
+	}
+	version := decoded.Version()
+	for _, name := range allowed {
+		if name == version {
+			h.Header = decoded.Header
+			h.invalidateHash()
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrVersionNotAllowed, "version %#v, allowed %q", version, allowed)
+}
+
+// Bytes returns the tagged RLP encoding of the header.
+func (h *Header) Bytes() ([]byte, error) {
+	if h == nil {
+		return nil, ErrHeaderIsNil
+	}
+	var buf bytes.Buffer
+	if err := h.EncodeRLP(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// HeaderFromBytes decodes a header from the tagged RLP encoding returned by
+// Bytes.  Trailing bytes after the encoding are an error.
+func HeaderFromBytes(b []byte) (*Header, error) {
+	h := &Header{}
+	if err := rlp.DecodeBytes(b, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// DecodeLegacyHeader decodes a bare legacy (v0) header RLP encoding, as found
+// in old databases, without consulting HeaderRegistry.  It only supports v0;
+// a tagged encoding of any other version is rejected with ErrNotLegacyHeader.
+// Trailing bytes after the encoding are an error.
+//
+// HeaderFromBytes also decodes bare v0 encodings, as the legacy version is
+// encoded without a tag; use DecodeLegacyHeader for migrations that must not
+// accept newer versions.
+func DecodeLegacyHeader(b []byte) (*Header, error) {
+	if version := encodedHeaderVersion(b); version != legacyVersionName {
+		return nil, errors.Wrapf(ErrNotLegacyHeader, "tagged as version %s", version)
+	}
+	hif := v0.NewHeader()
+	if err := rlp.DecodeBytes(b, hif); err != nil {
+		return nil, &HeaderDecodeError{Version: legacyVersionName, Err: err}
+	}
+	return &Header{Header: hif}, nil
+}
+
+// Hex returns the 0x-prefixed hex form of the tagged RLP encoding of the
+// header.
+func (h *Header) Hex() (string, error) {
+	b, err := h.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(b), nil
+}
+
+// HeaderFromHex decodes a header from the hex form of its tagged RLP encoding,
+// with or without a 0x prefix.
+func HeaderFromHex(s string) (*Header, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid header hex")
+	}
+	return HeaderFromBytes(b)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the tagged RLP
+// encoding of the header.
+func (h *Header) MarshalBinary() ([]byte, error) {
+	return h.Bytes()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding the form
+// produced by MarshalBinary.
+func (h *Header) UnmarshalBinary(data []byte) error {
+	if h == nil {
+		return ErrHeaderIsNil
+	}
+	decoded, err := HeaderFromBytes(data)
+	if err != nil {
+		return err
+	}
+	h.Header = decoded.Header
+	h.invalidateHash()
 	return nil
 }
 
+// decodeTaggedHeader decodes the given tagged RLP header encoding using the
+// registry.  A panic in the versioned decoder, e.g. on malformed network
+// input, is returned as an error naming the header version.
+func decodeTaggedHeader(raw []byte, reg *taggedrlp.Registry) (decoded interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			decoded, err = nil, errors.Errorf(
+				"panic while decoding header version %s: %v", encodedHeaderVersion(raw), r)
+		}
+	}()
+	return reg.Decode(rlp.NewStream(bytes.NewReader(raw), uint64(len(raw))))
+}
+
+// EncodedSize returns the length of the tagged RLP encoding of the header,
+// including the version tag envelope, without retaining the encoded bytes.
+//
+// Use Size for the approximate in-memory size instead.
+func (h *Header) EncodedSize() (int, error) {
+	var w countingWriter
+	if err := h.EncodeRLP(&w); err != nil {
+		return 0, err
+	}
+	return w.n, nil
+}
+
+// countingWriter is an io.Writer that discards and counts the bytes written.
+type countingWriter struct {
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
+
 // Hash returns the block hash of the header.  This uses HeaderRegistry to
 // choose and return the right tagged RLP form of the header.
+//
+// The hash is computed on the first call and cached until a field is set
+// through the header or its field setter.
 func (h *Header) Hash() common.Hash {
+	if cached, _ := h.cachedHash.Load().(*common.Hash); cached != nil {
+		return *cached
+	}
+	hash := h.HashNoCache()
+	h.cachedHash.Store(&hash)
+	return hash
+}
+
+// HashNoCache is like Hash, but always recomputes the hash, ignoring and
+// leaving intact the cached one.  Use it after mutating the embedded
+// blockif.Header directly.
+func (h *Header) HashNoCache() common.Hash {
+	if hash, err := hashHeaderEncoding(h); err == nil {
+		return hash
+	}
+	// Keep the historical result for headers that cannot be encoded.
 	return hash.FromRLP(h)
 }
 
+// HashWith returns the hash of the tagged RLP encoding of the header, as
+// returned by Bytes, under the given hash function.  With Keccak256 it yields
+// the same value as Hash; it exists to evaluate alternate hash functions, and
+// does not use or update the cached hash.
+func (h *Header) HashWith(hasher func([]byte) common.Hash) (common.Hash, error) {
+	b, err := h.Bytes()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return hasher(b), nil
+}
+
+// SealHash returns the hash of the header with its commit signature fields,
+// LastCommitSignature and LastCommitBitmap, cleared; this is the hash that
+// consensus signers sign.  For a header without a commit signature, it equals
+// Hash.
+func (h *Header) SealHash() (common.Hash, error) {
+	if isNilHeader(h) {
+		return common.Hash{}, ErrHeaderIsNil
+	}
+	unsigned := h.Copy()
+	unsigned.SetLastCommitSignature([96]byte{})
+	unsigned.SetLastCommitBitmap(nil)
+	return hashHeader(unsigned)
+}
+
 // Logger returns a sub-logger with block contexts added.
+//
+// The view ID is added only for header versions known to carry one.
 func (h *Header) Logger(logger *zerolog.Logger) *zerolog.Logger {
-	nlogger := logger.With().
+	ctx := logger.With().
 		Str("blockHash", h.Hash().Hex()).
 		Uint32("blockShard", h.ShardID()).
-		Uint64("blockEpoch", h.Epoch().Uint64()).
-		Uint64("blockNumber", h.Number().Uint64()).
+		Uint64("blockEpoch", h.EpochNumber())
+	number, err := h.NumberU64()
+	ctx = ctx.Uint64("blockNumber", number).AnErr("blockNumberError", err)
+	if v, _ := headerVersionOf(h.Header); v.features.viewID {
+		ctx = ctx.Uint64("blockViewID", bigUint64OrZero(h.ViewID))
+	}
+	nlogger := ctx.
+		Bool("epochLastBlock", h.IsLastBlockInEpoch()).
 		Logger()
 	return &nlogger
 }
 
+// EpochNumber returns the epoch number as a uint64, or 0 if the epoch is not
+// set, as in partially constructed or malformed headers.  Unlike
+// Epoch().Uint64(), it never panics.
+func (h *Header) EpochNumber() uint64 {
+	if isNilHeader(h) {
+		return 0
+	}
+	return bigUint64OrZero(h.Epoch)
+}
+
+// NumberU64 returns the block number as a uint64.  Unlike Number().Uint64(),
+// it neither panics on an unset number nor silently truncates one beyond the
+// uint64 range; it returns ErrNumberUnset or ErrNumberOverflow instead.
+func (h *Header) NumberU64() (uint64, error) {
+	if isNilHeader(h) {
+		return 0, ErrHeaderIsNil
+	}
+	number := bigOrNil(h.Number)
+	switch {
+	case number == nil:
+		return 0, ErrNumberUnset
+	case !number.IsUint64():
+		return 0, errors.Wrapf(ErrNumberOverflow, "number=%v", number)
+	}
+	return number.Uint64(), nil
+}
+
+// MustNumberU64 is like NumberU64, but panics on error.  Use it in tests.
+func (h *Header) MustNumberU64() uint64 {
+	number, err := h.NumberU64()
+	if err != nil {
+		panic(err)
+	}
+	return number
+}
+
+// bigUint64OrZero returns the uint64 value of the big integer returned by get,
+// or 0 if it is unset.
+func bigUint64OrZero(get func() *big.Int) uint64 {
+	if x := bigOrNil(get); x != nil {
+		return x.Uint64()
+	}
+	return 0
+}
+
+// bigOrNil returns the big integer returned by get, or nil if the field is
+// unset.  The versioned header getters panic on unset big integer fields, so
+// such a panic also yields nil.
+func bigOrNil(get func() *big.Int) (x *big.Int) {
+	defer func() {
+		if recover() != nil {
+			x = nil
+		}
+	}()
+	return get()
+}
+
+// WithDefaults sets unset fields of the header to their zero values: nil
+// Number, Epoch, Time, and ViewID to 0, and nil byte slice fields carried by
+// the header version to empty slices.  Fields already set are left untouched.
+// GasLimit and GasUsed are plain integers and thus always set.
+//
+// This does not change the header hash, as RLP encodes unset and zero values
+// alike, but it makes the getters safe to use on partially constructed
+// headers.  WithDefaults returns the receiver for chaining.
+func (h *Header) WithDefaults() *Header {
+	if isNilHeader(h) {
+		return h
+	}
+	bigFields := []struct {
+		get func() *big.Int
+		set func(*big.Int)
+	}{
+		{h.Number, h.SetNumber},
+		{h.Epoch, h.SetEpoch},
+		{h.Time, h.SetTime},
+		{h.ViewID, h.SetViewID},
+	}
+	for _, f := range bigFields {
+		if bigOrNil(f.get) == nil {
+			f.set(big.NewInt(0))
+		}
+	}
+	v, _ := headerVersionOf(h.Header)
+	bytesFields := []struct {
+		carried bool
+		get     func() []byte
+		set     func([]byte)
+	}{
+		{true, h.Extra, h.SetExtra},
+		{true, h.LastCommitBitmap, h.SetLastCommitBitmap},
+		{true, h.ShardState, h.SetShardState},
+		{v.features.randomness, h.Vrf, h.SetVrf},
+		{v.features.randomness, h.Vdf, h.SetVdf},
+		{v.features.crossLinks, h.CrossLinks, h.SetCrossLinks},
+		{v.features.slashes, h.Slashes, h.SetSlashes},
+	}
+	for _, f := range bytesFields {
+		if f.carried && f.get() == nil {
+			f.set([]byte{})
+		}
+	}
+	return h
+}
+
 // With returns a field setter context for the header.
 //
 // Call a chain of setters on the returned field setter, followed by a call of
@@ -147,22 +799,455 @@ func (h *Header) With() HeaderFieldSetter {
 	return HeaderFieldSetter{h: h}
 }
 
+// Patch applies the given setter chain to the header in place and returns the
+// header, discarding any cached hash.  Fields not set by fn are preserved.
+// Like the Header method of HeaderFieldSetter, it panics if fn sets an
+// invalid field value.  Example:
+//
+//	header.Patch(func(s HeaderFieldSetter) HeaderFieldSetter {
+//		return s.GasUsed(gasUsed).Root(root)
+//	})
+func (h *Header) Patch(fn func(HeaderFieldSetter) HeaderFieldSetter) *Header {
+	fn(h.With()).Header()
+	h.invalidateHash()
+	return h
+}
+
+// NewChild returns a field setter context for a new child header of h, of the
+// same header version.  The child is pre-populated with the parent hash, the
+// shard ID, the block number plus one, and the epoch of h, and is built in
+// the parent context of h (see ParentContext); call further setters to
+// override or complete it, followed by a call of Header method:
+//
+//	child := parent.NewChild().
+//		Time(big.NewInt(time.Now().Unix())).
+//		Header()
+//
+// NewChild panics if h is nil or of an unregistered header version.
+func (h *Header) NewChild() HeaderFieldSetter {
+	if isNilHeader(h) {
+		panic(ErrHeaderIsNil)
+	}
+	v, ok := headerVersionOf(h.Header)
+	if !ok {
+		panic(errors.Errorf("cannot create child of unregistered header type %T", h.Header))
+	}
+	return (&Header{Header: v.factory()}).With().
+		ParentContext(h).
+		ParentHash(h.Hash()).
+		ShardID(h.ShardID()).
+		Number(new(big.Int).Add(h.Number(), big.NewInt(1))).
+		Epoch(h.Epoch())
+}
+
+// Version returns the name of the header version, i.e. "legacy" for v0 and
+// the tag under which it is registered in HeaderRegistry otherwise.  It
+// returns an empty string if the underlying header type is not registered.
+func (h *Header) Version() string {
+	if h == nil {
+		return ""
+	}
+	return headerVersionName(h.Header)
+}
+
+// AsInterface returns the underlying versioned header, for version-specific
+// operations, or ErrHeaderIsNil if there is none.
+//
+// The returned header is shared, not copied.  Modifying it bypasses the hash
+// cache of h; prefer With or Patch, or call Copy first.
+func (h *Header) AsInterface() (blockif.Header, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	if v := reflect.ValueOf(h.Header); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, ErrHeaderIsNil
+	}
+	return h.Header, nil
+}
+
+// Copy returns a deep copy of the header.
+//
+// The copy shares no mutable memory with the original, so either may be
+// modified, e.g. via With(), without affecting the other.
+func (h *Header) Copy() *Header {
+	if h == nil {
+		return nil
+	}
+	if h.Header == nil {
+		return &Header{}
+	}
+	cpy := h.Header.Copy()
+	// Versioned Copy() is shallow; rebind reference-typed fields to copies.
+	cpy.SetNumber(h.Number())
+	cpy.SetTime(h.Time())
+	cpy.SetExtra(h.Extra())
+	cpy.SetViewID(h.ViewID())
+	cpy.SetEpoch(h.Epoch())
+	cpy.SetLastCommitBitmap(h.LastCommitBitmap())
+	cpy.SetShardState(h.ShardState())
+	v, _ := headerVersionOf(h.Header)
+	if v.features.randomness {
+		cpy.SetVrf(h.Vrf())
+		cpy.SetVdf(h.Vdf())
+	}
+	if v.features.crossLinks {
+		cpy.SetCrossLinks(h.CrossLinks())
+	}
+	if v.features.slashes {
+		cpy.SetSlashes(h.Slashes())
+	}
+	if v.features.baseFee {
+		cpy.SetBaseFee(h.BaseFee())
+	}
+	return &Header{Header: cpy}
+}
+
+// CloneViaRLP returns an independent copy of the header made by encoding it
+// through HeaderRegistry and decoding the result.  It is slower than Copy but
+// correct by construction, and thus serves as a reference for testing Copy.
+func (h *Header) CloneViaRLP() (*Header, error) {
+	b, err := h.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return HeaderFromBytes(b)
+}
+
+// GasUtilization returns the fraction of the gas limit used by the block,
+// which is in [0, 1] for headers that pass ValidateGas.  It returns 0 if the
+// gas limit is zero.
+func (h *Header) GasUtilization() float64 {
+	gasLimit := h.GasLimit()
+	if gasLimit == 0 {
+		return 0
+	}
+	return float64(h.GasUsed()) / float64(gasLimit)
+}
+
+// maxHeaderTimeOffset is the number of seconds from January 1, year 1 to the
+// Unix epoch, which time.Time adds to Unix seconds internally.
+const maxHeaderTimeOffset = 62135596800
+
+// MaxHeaderTime is the latest time that TimeAsTime returns; later header
+// timestamps are clamped to it.  It is the latest time whose Unix seconds
+// time.Unix can represent without overflow.
+var MaxHeaderTime = time.Unix(math.MaxInt64-maxHeaderTimeOffset, 0).UTC()
+
+// TimeAsTime returns the header timestamp as a time.Time, in UTC.  Timestamps
+// beyond MaxHeaderTime are clamped to it, and negative ones to the Unix epoch.
+func (h *Header) TimeAsTime() time.Time {
+	seconds := h.Time()
+	switch {
+	case seconds.Sign() < 0:
+		return time.Unix(0, 0).UTC()
+	case !seconds.IsInt64() || seconds.Int64() > MaxHeaderTime.Unix():
+		return MaxHeaderTime
+	default:
+		return time.Unix(seconds.Int64(), 0).UTC()
+	}
+}
+
+// Age returns how long before now the block was created according to its
+// timestamp.  It is negative if the timestamp is after now.
+func (h *Header) Age(now time.Time) time.Duration {
+	return now.Sub(h.TimeAsTime())
+}
+
+// CoinbaseBech32 returns the coinbase address of the header in the Harmony
+// bech32 form, e.g. "one1...".
+func (h *Header) CoinbaseBech32() (string, error) {
+	if isNilHeader(h) {
+		return "", ErrHeaderIsNil
+	}
+	return common2.AddressToBech32(h.Coinbase())
+}
+
+// IsGenesis returns whether this is a genesis block header, i.e. it has block
+// number zero and no parent.
+func (h *Header) IsGenesis() bool {
+	if isNilHeader(h) {
+		return false
+	}
+	number := h.Number()
+	return number != nil && number.Sign() == 0 && h.ParentHash() == (common.Hash{})
+}
+
+// IsEmpty returns whether the header carries no real block data: either it
+// has no underlying header, or its core fields – number, epoch, timestamp,
+// shard ID, parent hash, coinbase, trie roots, and gas – are all zero, as in
+// a freshly allocated header.
+func (h *Header) IsEmpty() bool {
+	if isNilHeader(h) {
+		return true
+	}
+	for _, get := range []func() *big.Int{h.Number, h.Epoch, h.Time} {
+		if x := bigOrNil(get); x != nil && x.Sign() != 0 {
+			return false
+		}
+	}
+	return h.ShardID() == 0 &&
+		h.ParentHash() == (common.Hash{}) &&
+		h.Coinbase() == (common.Address{}) &&
+		h.Root() == (common.Hash{}) &&
+		h.TxHash() == (common.Hash{}) &&
+		h.ReceiptHash() == (common.Hash{}) &&
+		h.GasLimit() == 0 &&
+		h.GasUsed() == 0
+}
+
+// ShardStateBytesHash returns the Keccak256 hash of the raw ShardState bytes,
+// or the zero hash if there is no shard state.  It allows cheap comparison of
+// the shard states of last blocks of epochs.  Unlike ShardStateHash, it does
+// not return the separately stored shard state root of v0-v2 headers.
+func (h *Header) ShardStateBytesHash() common.Hash {
+	shardState := h.ShardState()
+	if len(shardState) == 0 {
+		return common.Hash{}
+	}
+	return hash.Keccak256Hash(shardState)
+}
+
+// TrimShardState returns a copy of the header with the shard state cleared,
+// for relaying the last block of an epoch to peers that already have, and
+// will reattach, the shard state.  The header itself is unchanged.
+//
+// The shard state is covered by the header hash, so the hash of the trimmed
+// copy differs from that of the header unless it carries no shard state.
+func (h *Header) TrimShardState() *Header {
+	if isNilHeader(h) {
+		return h.Copy()
+	}
+	trimmed := h.Copy()
+	trimmed.SetShardState(nil)
+	trimmed.invalidateHash()
+	return trimmed
+}
+
+// DecodedShardState returns the shard state embedded in the header, decoded
+// the same way as the versioned headers' GetShardState.  It returns
+// ErrNoShardState if the header carries no shard state, i.e. it is not the
+// last block of an epoch.
+func (h *Header) DecodedShardState() (*shard.State, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	shardState := h.ShardState()
+	if len(shardState) == 0 {
+		return nil, ErrNoShardState
+	}
+	state, err := shard.DecodeWrapper(shardState)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot decode shard state")
+	}
+	return state, nil
+}
+
+// VRFProof returns the VRF output and proof of the header, and whether the
+// header version carries it.  Versions without randomness fields return
+// (nil, false).
+func (h *Header) VRFProof() ([]byte, bool) {
+	if v, _ := headerVersionOf(h.Header); !v.features.randomness {
+		return nil, false
+	}
+	return h.Vrf(), true
+}
+
+// VDFProof returns the VDF output and proof of the header, and whether the
+// header version carries it.  Versions without randomness fields return
+// (nil, false).
+func (h *Header) VDFProof() ([]byte, bool) {
+	if v, _ := headerVersionOf(h.Header); !v.features.randomness {
+		return nil, false
+	}
+	return h.Vdf(), true
+}
+
 // IsLastBlockInEpoch returns True if it is the last block of the epoch.
 // Note that the last block contains the shard state of the next epoch.
 func (h *Header) IsLastBlockInEpoch() bool {
 	return len(h.ShardState()) > 0
 }
 
+// EpochTransition returns whether the header is the last block of its epoch,
+// and if so, the shard state for the next epoch that it carries.  For other
+// headers it returns (false, nil, nil).  If the header carries a shard state
+// that cannot be decoded, isTransition is true and err is the decoding error.
+func (h *Header) EpochTransition() (isTransition bool, nextState *shard.State, err error) {
+	if isNilHeader(h) {
+		return false, nil, ErrHeaderIsNil
+	}
+	if !h.IsLastBlockInEpoch() {
+		return false, nil, nil
+	}
+	nextState, err = h.DecodedShardState()
+	if err != nil {
+		return true, nil, err
+	}
+	return true, nextState, nil
+}
+
+// IsFirstBlockInEpoch returns whether this is the first block of its epoch,
+// i.e. its epoch is greater than that of the given parent.  The genesis block
+// is the first block of its epoch; a nil parent is taken to mean that h is the
+// genesis block.
+func (h *Header) IsFirstBlockInEpoch(parent *Header) bool {
+	if isNilHeader(parent) || h.IsGenesis() {
+		return true
+	}
+	return h.Epoch().Cmp(parent.Epoch()) > 0
+}
+
 // HeaderRegistry is the taggedrlp type registry for versioned headers.
-var HeaderRegistry = taggedrlp.NewRegistry()
-
-func init() {
-	HeaderRegistry.MustRegister(taggedrlp.LegacyTag, v0.NewHeader())
-	HeaderRegistry.MustAddFactory(func() interface{} { return v0.NewHeader() })
-	HeaderRegistry.MustRegister("v1", v1.NewHeader())
-	HeaderRegistry.MustAddFactory(func() interface{} { return v1.NewHeader() })
-	HeaderRegistry.MustRegister("v2", v2.NewHeader())
-	HeaderRegistry.MustAddFactory(func() interface{} { return v2.NewHeader() })
-	HeaderRegistry.MustRegister("v3", v3.NewHeader())
-	HeaderRegistry.MustAddFactory(func() interface{} { return v3.NewHeader() })
+//
+// It is populated with all header versions of this package at initialization.
+// Add custom versions with RegisterHeaderVersion rather than modifying it
+// directly; encoding and decoding only read it and are thus safe for
+// concurrent use.  Use NewHeaderRegistry to obtain an isolated registry that
+// may be customized.
+var HeaderRegistry = defaultHeaderRegistry.reg
+
+// legacyVersionName is the version name of the legacy (v0) header, which is
+// registered under the empty tag.
+const legacyVersionName = "legacy"
+
+// headerVersion describes one header version known to this package.
+type headerVersion struct {
+	name     string
+	tag      taggedrlp.Tag
+	factory  func() blockif.Header
+	features headerFeatures
+}
+
+// headerFeatures describes which optional fields a header version carries.
+// Header versions silently ignore setting a field they do not carry.
+type headerFeatures struct {
+	viewID             bool // ViewID
+	crossShardReceipts bool // OutgoingReceiptHash and IncomingReceiptHash
+	shardStateRoot     bool // stored ShardStateHash
+	randomness         bool // Vrf and Vdf
+	crossLinks         bool // CrossLinks
+	slashes            bool // Slashes
+	baseFee            bool // BaseFee
+}
+
+// headerVersions lists all header versions, oldest first.  It is the source
+// of truth from which HeaderRegistry is populated.
+var headerVersions = []headerVersion{
+	{
+		name:    legacyVersionName,
+		tag:     taggedrlp.LegacyTag,
+		factory: func() blockif.Header { return v0.NewHeader() },
+		features: headerFeatures{
+			viewID:         true,
+			shardStateRoot: true,
+		},
+	},
+	{
+		name:    "v1",
+		tag:     "v1",
+		factory: func() blockif.Header { return v1.NewHeader() },
+		features: headerFeatures{
+			viewID:             true,
+			crossShardReceipts: true,
+			shardStateRoot:     true,
+			randomness:         true,
+		},
+	},
+	{
+		name:    "v2",
+		tag:     "v2",
+		factory: func() blockif.Header { return v2.NewHeader() },
+		features: headerFeatures{
+			viewID:             true,
+			crossShardReceipts: true,
+			shardStateRoot:     true,
+			randomness:         true,
+			crossLinks:         true,
+		},
+	},
+	{
+		name:    "v3",
+		tag:     "v3",
+		factory: func() blockif.Header { return v3.NewHeader() },
+		features: headerFeatures{
+			viewID:             true,
+			crossShardReceipts: true,
+			randomness:         true,
+			crossLinks:         true,
+			slashes:            true,
+		},
+	},
+	{
+		name:    "v4",
+		tag:     "v4",
+		factory: func() blockif.Header { return v4.NewHeader() },
+		features: headerFeatures{
+			viewID:             true,
+			crossShardReceipts: true,
+			randomness:         true,
+			crossLinks:         true,
+			slashes:            true,
+			baseFee:            true,
+		},
+	},
+}
+
+// All header versions implement blockif.Header.
+var (
+	_ blockif.Header = (*v0.Header)(nil)
+	_ blockif.Header = (*v1.Header)(nil)
+	_ blockif.Header = (*v2.Header)(nil)
+	_ blockif.Header = (*v3.Header)(nil)
+	_ blockif.Header = (*v4.Header)(nil)
+)
+
+// headerVersionsByTag maps the tags of headerVersions to their versions.
+var headerVersionsByTag = func() map[taggedrlp.Tag]headerVersion {
+	versions := make(map[taggedrlp.Tag]headerVersion, len(headerVersions))
+	for _, v := range headerVersions {
+		versions[v.tag] = v
+	}
+	return versions
+}()
+
+// headerVersionOf returns the version of the given header implementation,
+// looked up by the tag under which its type is registered in HeaderRegistry.
+// It fails for unregistered types and for custom versions, which this
+// package has no feature description of.
+func headerVersionOf(hif blockif.Header) (headerVersion, bool) {
+	tag, ok := defaultHeaderRegistry.tagOf(hif)
+	if !ok {
+		return headerVersion{}, false
+	}
+	v, ok := headerVersionsByTag[tag]
+	return v, ok
+}
+
+// headerVersionName returns the version name of the given header
+// implementation as registered in HeaderRegistry, or an empty string if it is
+// not registered.
+func headerVersionName(hif blockif.Header) string {
+	return defaultHeaderRegistry.versionName(hif)
+}
+
+// headerVersionByName returns the header version with the given name.
+func headerVersionByName(name string) (headerVersion, bool) {
+	for _, v := range headerVersions {
+		if v.name == name {
+			return v, true
+		}
+	}
+	return headerVersion{}, false
+}
+
+// NewHeaderRegistry returns a new taggedrlp type registry populated with all
+// header versions, independent of HeaderRegistry.
+func NewHeaderRegistry() *taggedrlp.Registry {
+	return newHeaderRegistry().reg
+}
+
+// SupportedHeaderVersions returns the sorted names of the header versions
+// registered in HeaderRegistry, with "legacy" standing for the untagged v0.
+func SupportedHeaderVersions() []string {
+	return defaultHeaderRegistry.versionNames()
 }
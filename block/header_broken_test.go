@@ -0,0 +1,25 @@
+//go:build brokenheader
+// +build brokenheader
+
+package block
+
+import (
+	blockif "github.com/harmony-one/harmony/block/interface"
+)
+
+// brokenHeader documents the failure mode of a header version that does not
+// fully implement blockif.Header.  Building the tests with
+//
+//	go test -tags brokenheader ./block
+//
+// fails to compile with an error naming the first missing method, like:
+//
+//	cannot use (*brokenHeader)(nil) (value of type *brokenHeader) as
+//	blockif.Header value in variable declaration: *brokenHeader does not
+//	implement blockif.Header (missing method BaseFee)
+//
+// The assertions next to headerVersions catch a partially implemented new
+// version the same way.
+type brokenHeader struct{}
+
+var _ blockif.Header = (*brokenHeader)(nil)
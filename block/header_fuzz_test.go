@@ -0,0 +1,38 @@
+//go:build go1.18
+// +build go1.18
+
+package block
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// FuzzHeaderDecode checks that decoding arbitrary input never panics.
+// Run it with:
+//
+//	go test -run XXX -fuzz FuzzHeaderDecode ./block
+func FuzzHeaderDecode(f *testing.F) {
+	for _, v := range headerVersions {
+		h := (&Header{Header: v.factory()}).With().Extra([]byte("seed")).Header()
+		b, err := h.Bytes()
+		if err != nil {
+			f.Fatalf("cannot encode %s seed header: %v", v.name, err)
+		}
+		f.Add(b)
+	}
+	f.Add([]byte{})
+	f.Add([]byte{0xc0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var h Header
+		if err := rlp.DecodeBytes(data, &h); err != nil {
+			return
+		}
+		// A successfully decoded header must be usable.
+		if _, err := h.Bytes(); err != nil {
+			t.Errorf("cannot re-encode decoded header: %v", err)
+		}
+		h.Hash()
+	})
+}
@@ -2,15 +2,35 @@ package block
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
 
 	blockif "github.com/harmony-one/harmony/block/interface"
 	v0 "github.com/harmony-one/harmony/block/v0"
 	v1 "github.com/harmony-one/harmony/block/v1"
 	v2 "github.com/harmony-one/harmony/block/v2"
+	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
+	"github.com/harmony-one/harmony/crypto/hash"
+	common2 "github.com/harmony-one/harmony/internal/common"
+	"github.com/harmony-one/harmony/shard"
+	"github.com/harmony-one/taggedrlp"
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/sha3"
 )
 
 func TestHeader_EncodeRLP(t *testing.T) {
@@ -780,3 +800,1547 @@ func compareHeaders(x, y blockif.Header) bool {
 		equal(x.ShardState(), y.ShardState()) &&
 		equal(x.CrossLinks(), y.CrossLinks())
 }
+
+func TestHeader_JSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		hif  blockif.Header
+	}{
+		{"v0", v0.NewHeader()},
+		{"v1", v1.NewHeader()},
+		{"v2", v2.NewHeader()},
+		{"v3", v3.NewHeader()},
+		{"v4", v4.NewHeader()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := (&Header{Header: tt.hif}).With().
+				ParentHash(common.HexToHash("0x1234")).
+				Coinbase(common.HexToAddress("0x5678")).
+				Number(big.NewInt(100)).
+				GasLimit(80000000).
+				GasUsed(21000).
+				Time(big.NewInt(1600000000)).
+				Extra([]byte("extra")).
+				ViewID(big.NewInt(105)).
+				Epoch(big.NewInt(7)).
+				ShardID(1).
+				LastCommitSignature([96]byte{1, 2, 3}).
+				LastCommitBitmap([]byte{0xff, 0x01}).
+				ShardState([]byte{0xc0}).
+				Vrf([]byte{4, 5}).
+				Vdf([]byte{6, 7}).
+				CrossLinks([]byte{0xc1, 0x80}).
+				Header()
+			if v, _ := headerVersionOf(tt.hif); v.features.slashes {
+				h.SetSlashes([]byte{0xc0})
+			}
+			if v, _ := headerVersionOf(tt.hif); v.features.baseFee {
+				h.SetBaseFee(big.NewInt(1000000000))
+			}
+			b, err := json.Marshal(h)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			got := &Header{}
+			if err := json.Unmarshal(b, got); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if reflect.TypeOf(got.Header) != reflect.TypeOf(tt.hif) {
+				t.Errorf("UnmarshalJSON() got type %T, want %T", got.Header, tt.hif)
+			}
+			if got.Hash() != h.Hash() {
+				t.Errorf("UnmarshalJSON() got hash %x, want %x", got.Hash(), h.Hash())
+			}
+		})
+	}
+}
+
+// TestHeader_MarshalJSON_VersionedFields checks that every JSON-tagged field
+// of each versioned header struct surfaces in the wrapper JSON, so that a
+// field added to a header version cannot silently go missing from it.
+func TestHeader_MarshalJSON_VersionedFields(t *testing.T) {
+	for _, v := range headerVersions {
+		t.Run(v.name, func(t *testing.T) {
+			hif := v.factory()
+			b, err := json.Marshal(&Header{Header: hif})
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			var got map[string]json.RawMessage
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("cannot parse MarshalJSON() output: %v", err)
+			}
+			fields := reflect.TypeOf(hif).Elem().Field(0).Type
+			for i := 0; i < fields.NumField(); i++ {
+				field := fields.Field(i)
+				key := headerJSONKey(field)
+				if key == "" {
+					t.Errorf("%s.%s has no JSON key", fields, field.Name)
+					continue
+				}
+				if value, ok := got[key]; !ok || string(value) == "null" {
+					t.Errorf("%s.%s: MarshalJSON() key %#v missing or null",
+						fields, field.Name, key)
+				}
+			}
+		})
+	}
+}
+
+// extendedHeader is a mock header version with a field that the wrapper JSON
+// representation does not know about.
+type extendedHeader struct {
+	*v3.Header
+}
+
+func (h *extendedHeader) MarshalFieldsJSON() ([]byte, error) {
+	b, err := h.Header.MarshalFieldsJSON()
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes.TrimSuffix(b, []byte("}")), `,"sideChainID":42}`...), nil
+}
+
+func TestHeader_MarshalJSON_NewField(t *testing.T) {
+	h := (&Header{Header: &extendedHeader{v3.NewHeader()}}).With().
+		Number(big.NewInt(16)).Header()
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("cannot parse MarshalJSON() output %s: %v", b, err)
+	}
+	if id := string(got["sideChainID"]); id != "42" {
+		t.Errorf("MarshalJSON() sideChainID = %s, want 42", id)
+	}
+	// Fields known to the wrapper keep their RPC formats.
+	if number := string(got["number"]); number != `"0x10"` {
+		t.Errorf("MarshalJSON() number = %s, want \"0x10\"", number)
+	}
+
+	// Built-in versions have no fields beyond the wrapper representation.
+	for _, v := range headerVersions {
+		h := &Header{Header: v.factory()}
+		got, err := json.Marshal(h)
+		if err != nil {
+			t.Fatalf("%s: MarshalJSON() error = %v", v.name, err)
+		}
+		want, _ := json.Marshal(newHeaderJSON(h))
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: MarshalJSON() = %s, want %s", v.name, got, want)
+		}
+	}
+}
+
+func TestHeader_UnmarshalJSON_NoVersion(t *testing.T) {
+	got := &Header{}
+	if err := json.Unmarshal([]byte(`{"number":"0x1","epoch":"2"}`), got); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if _, ok := got.Header.(*v0.Header); !ok {
+		t.Errorf("UnmarshalJSON() got type %T, want *v0.Header", got.Header)
+	}
+	if got.Number().Cmp(big.NewInt(1)) != 0 || got.Epoch().Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("UnmarshalJSON() got number %v epoch %v", got.Number(), got.Epoch())
+	}
+}
+
+func TestHeader_UnmarshalJSON_UnknownVersion(t *testing.T) {
+	got := &Header{}
+	if err := json.Unmarshal([]byte(`{"version":"v99"}`), got); err == nil {
+		t.Error("UnmarshalJSON() expected error for unknown version")
+	}
+}
+
+func TestHeader_AsInterface(t *testing.T) {
+	hif := v3.NewHeader()
+	got, err := (&Header{Header: hif}).AsInterface()
+	if err != nil {
+		t.Fatalf("AsInterface() error = %v", err)
+	}
+	if got != hif {
+		t.Errorf("AsInterface() = %p, want %p", got, hif)
+	}
+	if _, ok := got.(*v3.Header); !ok {
+		t.Errorf("AsInterface() type = %T, want *v3.Header", got)
+	}
+
+	for name, h := range map[string]*Header{
+		"Nil":           nil,
+		"NilEmbedded":   {},
+		"TypedNilEmbed": {Header: (*v3.Header)(nil)},
+	} {
+		if got, err := h.AsInterface(); got != nil || err != ErrHeaderIsNil {
+			t.Errorf("%s: AsInterface() = %v, %v; want nil, %v", name, got, err, ErrHeaderIsNil)
+		}
+	}
+}
+
+func TestHeader_MarshalJSONWithRaw(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).Epoch(big.NewInt(5)).ShardID(1).
+		Extra([]byte("raw")).Header()
+	b, err := h.MarshalJSONWithRaw()
+	if err != nil {
+		t.Fatalf("MarshalJSONWithRaw() error = %v", err)
+	}
+	var got struct {
+		Number string        `json:"number"`
+		Raw    hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("cannot parse MarshalJSONWithRaw() output: %v", err)
+	}
+	if got.Number != "0x64" {
+		t.Errorf("MarshalJSONWithRaw() number = %#v, want %#v", got.Number, "0x64")
+	}
+	decoded, err := HeaderFromBytes(got.Raw)
+	if err != nil {
+		t.Fatalf("cannot decode raw field: %v", err)
+	}
+	if !decoded.Equal(h) {
+		t.Errorf("raw field decodes to %v, want %v", decoded, h)
+	}
+
+	// The raw field does not get in the way of UnmarshalJSON.
+	var unmarshaled Header
+	if err := json.Unmarshal(b, &unmarshaled); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if unmarshaled.Hash() != h.Hash() {
+		t.Errorf("UnmarshalJSON() hash = %s, want %s", unmarshaled.Hash().Hex(), h.Hash().Hex())
+	}
+
+	if _, err := (*Header)(nil).MarshalJSONWithRaw(); err != ErrHeaderIsNil {
+		t.Errorf("MarshalJSONWithRaw() on nil header error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
+
+func TestHeader_MarshalJSON_Keys(t *testing.T) {
+	common := []string{
+		"parentHash", "sha3Uncles", "nonce", "miner", "minerBech32", "stateRoot",
+		"transactionsRoot", "receiptsRoot", "logsBloom", "difficulty",
+		"number", "gasLimit", "gasUsed", "timestamp", "extraData", "mixHash",
+		"hash", "viewID", "epoch", "shardID", "lastCommitSignature",
+		"lastCommitBitmap", "shardStateHash", "shardState", "version",
+	}
+	optional := []string{
+		"outgoingReceiptsRoot", "incomingReceiptsRoot", "shardStateRoot",
+		"vrf", "vdf", "crossLinks", "slashes",
+	}
+	tests := []struct {
+		name    string
+		hif     blockif.Header
+		notNull []string
+		baseFee bool
+	}{
+		{"v0", v0.NewHeader(), []string{"shardStateRoot"}, false},
+		{"v1", v1.NewHeader(), []string{
+			"outgoingReceiptsRoot", "incomingReceiptsRoot", "shardStateRoot",
+			"vrf", "vdf",
+		}, false},
+		{"v2", v2.NewHeader(), []string{
+			"outgoingReceiptsRoot", "incomingReceiptsRoot", "shardStateRoot",
+			"vrf", "vdf", "crossLinks",
+		}, false},
+		{"v3", v3.NewHeader(), []string{
+			"outgoingReceiptsRoot", "incomingReceiptsRoot",
+			"vrf", "vdf", "crossLinks", "slashes",
+		}, false},
+		{"v4", v4.NewHeader(), []string{
+			"outgoingReceiptsRoot", "incomingReceiptsRoot",
+			"vrf", "vdf", "crossLinks", "slashes",
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := (&Header{Header: tt.hif}).With().
+				Epoch(big.NewInt(12)).ViewID(big.NewInt(34)).ShardID(3).Header()
+			b, err := json.Marshal(h)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			var got map[string]json.RawMessage
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("cannot parse MarshalJSON() output: %v", err)
+			}
+			wantKeys := len(common) + len(optional)
+			if tt.baseFee {
+				wantKeys++
+			}
+			if len(got) != wantKeys {
+				t.Errorf("MarshalJSON() got %d keys, want %d", len(got), wantKeys)
+			}
+			if _, ok := got["baseFeePerGas"]; ok != tt.baseFee {
+				t.Errorf("MarshalJSON() has baseFeePerGas = %v, want %v", ok, tt.baseFee)
+			}
+			for _, key := range common {
+				if v, ok := got[key]; !ok || string(v) == "null" {
+					t.Errorf("MarshalJSON() key %#v missing or null", key)
+				}
+			}
+			notNull := map[string]bool{}
+			for _, key := range tt.notNull {
+				notNull[key] = true
+			}
+			for _, key := range optional {
+				v, ok := got[key]
+				if !ok {
+					t.Errorf("MarshalJSON() key %#v missing", key)
+				} else if isNull := string(v) == "null"; isNull == notNull[key] {
+					t.Errorf("MarshalJSON() key %#v got %s", key, v)
+				}
+			}
+			for key, want := range map[string]string{
+				"epoch": `"12"`, "viewID": `"34"`, "shardID": `"3"`,
+				"version": `"` + headerVersionName(tt.hif) + `"`,
+			} {
+				if string(got[key]) != want {
+					t.Errorf("MarshalJSON() key %#v got %s, want %s",
+						key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHeader_Copy(t *testing.T) {
+	for _, hif := range []blockif.Header{
+		v0.NewHeader(), v1.NewHeader(), v2.NewHeader(), v3.NewHeader(),
+		v4.NewHeader(),
+	} {
+		t.Run(headerVersionName(hif), func(t *testing.T) {
+			h := (&Header{Header: hif}).With().
+				Number(big.NewInt(10)).
+				Extra([]byte("original")).
+				ShardState([]byte{0xc0}).
+				Vrf([]byte{1, 2, 3}).
+				Header()
+			origHash := h.Hash()
+			cpy := h.Copy()
+			if cpy.Header == h.Header {
+				t.Fatal("Copy() shares the underlying header")
+			}
+			if cpy.Hash() != origHash {
+				t.Errorf("Copy() got hash %x, want %x", cpy.Hash(), origHash)
+			}
+			cpy.With().Number(big.NewInt(11)).Extra([]byte("modified"))
+			if h.Number().Cmp(big.NewInt(10)) != 0 {
+				t.Errorf("original number changed to %v", h.Number())
+			}
+			if !bytes.Equal(h.Extra(), []byte("original")) {
+				t.Errorf("original extra changed to %q", h.Extra())
+			}
+			if h.Hash() != origHash {
+				t.Error("original hash changed")
+			}
+			if cpy.Hash() == origHash {
+				t.Error("modified copy hash did not diverge")
+			}
+		})
+	}
+}
+
+func TestHeader_Copy_Nil(t *testing.T) {
+	var h *Header
+	if h.Copy() != nil {
+		t.Error("Copy() of nil header is not nil")
+	}
+}
+
+func TestHeader_EncodedSize(t *testing.T) {
+	for _, hif := range []blockif.Header{
+		v0.NewHeader(), v1.NewHeader(), v2.NewHeader(), v3.NewHeader(),
+		v4.NewHeader(),
+	} {
+		t.Run(headerVersionName(hif), func(t *testing.T) {
+			h := (&Header{Header: hif}).With().
+				Number(big.NewInt(1234567)).
+				Extra([]byte("some extra data")).
+				ShardState(make([]byte, 300)).
+				Header()
+			b, err := rlp.EncodeToBytes(h)
+			if err != nil {
+				t.Fatalf("EncodeToBytes() error = %v", err)
+			}
+			got, err := h.EncodedSize()
+			if err != nil {
+				t.Fatalf("EncodedSize() error = %v", err)
+			}
+			if got != len(b) {
+				t.Errorf("EncodedSize() = %d, want %d", got, len(b))
+			}
+		})
+	}
+}
+
+// Header keeps the in-memory Size of the versioned header.
+var _ interface{ Size() common.StorageSize } = (*Header)(nil)
+
+func TestHeader_EncodedSize_Nil(t *testing.T) {
+	var h *Header
+	if _, err := h.EncodedSize(); err != ErrHeaderIsNil {
+		t.Errorf("EncodedSize() error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
+
+func TestHeader_Hash_CacheInvalidation(t *testing.T) {
+	h := &Header{Header: v3.NewHeader()}
+	before := h.Hash()
+	if cached := h.Hash(); cached != before {
+		t.Fatalf("Hash() not stable: %x != %x", cached, before)
+	}
+	h.With().Number(big.NewInt(1))
+	after := h.Hash()
+	if after == before {
+		t.Error("Hash() did not change after .With().Number()")
+	}
+	if want := h.HashNoCache(); after != want {
+		t.Errorf("Hash() = %x, want %x", after, want)
+	}
+	h.SetEpoch(big.NewInt(2))
+	if got, want := h.Hash(), h.HashNoCache(); got != want {
+		t.Errorf("Hash() after SetEpoch() = %x, want %x", got, want)
+	}
+}
+
+func TestHeader_HashNoCache(t *testing.T) {
+	h := &Header{Header: v3.NewHeader()}
+	cached := h.Hash()
+	// Mutating the embedded header directly bypasses invalidation.
+	h.Header.SetNumber(big.NewInt(1))
+	if h.Hash() != cached {
+		t.Error("Hash() unexpectedly recomputed")
+	}
+	if h.HashNoCache() == cached {
+		t.Error("HashNoCache() returned the stale cached hash")
+	}
+}
+
+func BenchmarkHeader_Hash(b *testing.B) {
+	h := &Header{Header: v3.NewHeader()}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.Hash()
+	}
+}
+
+func BenchmarkHeader_HashNoCache(b *testing.B) {
+	h := &Header{Header: v3.NewHeader()}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.HashNoCache()
+	}
+}
+
+func TestNewHeaderRegistry(t *testing.T) {
+	full := NewHeaderRegistry()
+	alt := taggedrlp.NewRegistry()
+	alt.MustRegister("v3alt", v3.NewHeader())
+	alt.MustAddFactory(func() interface{} { return v3.NewHeader() })
+
+	h := (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(7)).Header()
+	var fullBuf, altBuf bytes.Buffer
+	if err := h.EncodeRLPWithRegistry(&fullBuf, full); err != nil {
+		t.Fatalf("EncodeRLPWithRegistry(full) error = %v", err)
+	}
+	if err := h.EncodeRLPWithRegistry(&altBuf, alt); err != nil {
+		t.Fatalf("EncodeRLPWithRegistry(alt) error = %v", err)
+	}
+	if bytes.Equal(fullBuf.Bytes(), altBuf.Bytes()) {
+		t.Error("registries with different tags produced identical encodings")
+	}
+	var defaultBuf bytes.Buffer
+	if err := h.EncodeRLP(&defaultBuf); err != nil {
+		t.Fatalf("EncodeRLP() error = %v", err)
+	}
+	if !bytes.Equal(fullBuf.Bytes(), defaultBuf.Bytes()) {
+		t.Error("NewHeaderRegistry() encoding differs from HeaderRegistry")
+	}
+
+	// The alternate registry knows only its own tag.
+	got := &Header{}
+	s := rlp.NewStream(bytes.NewReader(altBuf.Bytes()), 0)
+	if err := got.DecodeRLPWithRegistry(s, alt); err != nil {
+		t.Fatalf("DecodeRLPWithRegistry(alt) error = %v", err)
+	}
+	if got.Hash() != h.Hash() {
+		t.Errorf("DecodeRLPWithRegistry(alt) got hash %x, want %x", got.Hash(), h.Hash())
+	}
+	s = rlp.NewStream(bytes.NewReader(altBuf.Bytes()), 0)
+	if err := got.DecodeRLPWithRegistry(s, full); err == nil {
+		t.Error("DecodeRLPWithRegistry(full) decoded an alt-tagged header")
+	}
+	s = rlp.NewStream(bytes.NewReader(fullBuf.Bytes()), 0)
+	if err := got.DecodeRLPWithRegistry(s, alt); err == nil {
+		t.Error("DecodeRLPWithRegistry(alt) decoded a v3-tagged header")
+	}
+}
+
+// unregisteredHeader is a header type that is not in HeaderRegistry.
+type unregisteredHeader struct {
+	*v3.Header
+}
+
+func TestHeader_Version(t *testing.T) {
+	tests := []struct {
+		name string
+		h    *Header
+		want string
+	}{
+		{"v0", &Header{Header: v0.NewHeader()}, "legacy"},
+		{"v1", &Header{Header: v1.NewHeader()}, "v1"},
+		{"v2", &Header{Header: v2.NewHeader()}, "v2"},
+		{"v3", &Header{Header: v3.NewHeader()}, "v3"},
+		{"v4", &Header{Header: v4.NewHeader()}, "v4"},
+		{"Unregistered", &Header{Header: unregisteredHeader{v3.NewHeader()}}, ""},
+		{"NilEmbedded", &Header{}, ""},
+		{"Nil", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.h.Version(); got != tt.want {
+				t.Errorf("Version() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_V4_RLPRoundTrip(t *testing.T) {
+	h := (&Header{Header: v4.NewHeader()}).With().
+		Number(big.NewInt(100)).
+		Epoch(big.NewInt(7)).
+		Header()
+	h.SetSlashes([]byte{0xc0})
+	h.SetBaseFee(big.NewInt(1000000000))
+	b, err := rlp.EncodeToBytes(h)
+	if err != nil {
+		t.Fatalf("EncodeRLP() error = %v", err)
+	}
+	got := &Header{}
+	if err := rlp.DecodeBytes(b, got); err != nil {
+		t.Fatalf("DecodeRLP() error = %v", err)
+	}
+	if _, ok := got.Header.(*v4.Header); !ok {
+		t.Fatalf("DecodeRLP() got type %T, want *v4.Header", got.Header)
+	}
+	if got.BaseFee().Cmp(big.NewInt(1000000000)) != 0 {
+		t.Errorf("DecodeRLP() got base fee %v, want 1000000000", got.BaseFee())
+	}
+	if !got.Equal(h) {
+		t.Errorf("DecodeRLP() got %v, want %v", got, h)
+	}
+	if got.Hash() != h.Hash() {
+		t.Errorf("DecodeRLP() got hash %x, want %x", got.Hash(), h.Hash())
+	}
+}
+
+func TestHeaderFromBytes(t *testing.T) {
+	for _, hif := range []blockif.Header{
+		v0.NewHeader(), v1.NewHeader(), v2.NewHeader(), v3.NewHeader(),
+		v4.NewHeader(),
+	} {
+		t.Run(headerVersionName(hif), func(t *testing.T) {
+			h := (&Header{Header: hif}).With().
+				Number(big.NewInt(100)).
+				Epoch(big.NewInt(7)).
+				ShardID(2).
+				Extra([]byte("extra")).
+				Header()
+			b, err := h.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes() error = %v", err)
+			}
+			got, err := HeaderFromBytes(b)
+			if err != nil {
+				t.Fatalf("HeaderFromBytes() error = %v", err)
+			}
+			if reflect.TypeOf(got.Header) != reflect.TypeOf(hif) {
+				t.Errorf("HeaderFromBytes() got type %T, want %T", got.Header, hif)
+			}
+			if !got.Equal(h) {
+				t.Errorf("HeaderFromBytes() got %v, want %v", got, h)
+			}
+		})
+	}
+}
+
+func TestHeader_Bytes_Nil(t *testing.T) {
+	var h *Header
+	if _, err := h.Bytes(); err != ErrHeaderIsNil {
+		t.Errorf("Bytes() error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
+
+func TestHeader_EncodeRLPInto(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		h := randomHeader(rnd)
+		b, err := h.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes() error = %v", err)
+		}
+		want := sha3.NewLegacyKeccak256()
+		want.Write(b)
+		got := sha3.NewLegacyKeccak256()
+		if err := h.EncodeRLPInto(got); err != nil {
+			t.Fatalf("EncodeRLPInto() error = %v", err)
+		}
+		if !bytes.Equal(got.Sum(nil), want.Sum(nil)) {
+			t.Errorf("%s header: streamed digest %x, want %x",
+				h.Version(), got.Sum(nil), want.Sum(nil))
+		}
+	}
+	var h *Header
+	if err := h.EncodeRLPInto(sha3.NewLegacyKeccak256()); err != ErrHeaderIsNil {
+		t.Errorf("EncodeRLPInto() error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
+
+func TestDecodeLegacyHeader(t *testing.T) {
+	legacy := v0.NewHeader()
+	legacy.SetNumber(big.NewInt(7))
+	legacy.SetExtra([]byte("old"))
+	bare, err := rlp.EncodeToBytes(legacy)
+	if err != nil {
+		t.Fatalf("cannot encode bare v0 header: %v", err)
+	}
+	h, err := DecodeLegacyHeader(bare)
+	if err != nil {
+		t.Fatalf("DecodeLegacyHeader() error = %v", err)
+	}
+	if h.Version() != legacyVersionName || h.Number().Cmp(big.NewInt(7)) != 0 ||
+		string(h.Extra()) != "old" {
+		t.Errorf("DecodeLegacyHeader() = %v, want v0 header number 7 extra \"old\"", h)
+	}
+
+	tagged, err := (&Header{Header: v3.NewHeader()}).Bytes()
+	if err != nil {
+		t.Fatalf("cannot encode v3 header: %v", err)
+	}
+	if _, err := DecodeLegacyHeader(tagged); errors.Cause(err) != ErrNotLegacyHeader ||
+		!strings.Contains(err.Error(), "v3") {
+		t.Errorf("DecodeLegacyHeader() of tagged header error = %v, want %v naming v3",
+			err, ErrNotLegacyHeader)
+	}
+
+	var decodeErr *HeaderDecodeError
+	if _, err := DecodeLegacyHeader([]byte{0xc1, 0xff}); !errors.As(err, &decodeErr) {
+		t.Errorf("DecodeLegacyHeader() of corrupt header error = %v, want *HeaderDecodeError", err)
+	}
+}
+
+func TestHeaderFromBytes_Invalid(t *testing.T) {
+	b, err := (&Header{Header: v3.NewHeader()}).Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	for name, input := range map[string][]byte{
+		"Empty":     nil,
+		"Truncated": b[:len(b)-1],
+		"Trailing":  append(b, 0x80),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := HeaderFromBytes(input); err == nil {
+				t.Error("HeaderFromBytes() expected error")
+			}
+		})
+	}
+}
+
+func TestHeader_IsGenesis(t *testing.T) {
+	tests := []struct {
+		name string
+		h    *Header
+		want bool
+	}{
+		{"Genesis", (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(0)).Header(), true},
+		{"ZeroWithParent", (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(0)).ParentHash(common.HexToHash("0x1234")).Header(), false},
+		{"Normal", (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(100)).ParentHash(common.HexToHash("0x1234")).Header(), false},
+		{"NilEmbedded", &Header{}, false},
+		{"Nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.h.IsGenesis(); got != tt.want {
+				t.Errorf("IsGenesis() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_CoinbaseBech32(t *testing.T) {
+	addr := common.HexToAddress("0xe923e18541c46d2e8d1eec54a67230e9e450f3b7")
+	const want = "one1ay37rp2pc3kjarg7a322vu3sa8j9puahg679z3"
+	h := (&Header{Header: v3.NewHeader()}).With().Coinbase(addr).Header()
+	got, err := h.CoinbaseBech32()
+	if err != nil || got != want {
+		t.Errorf("CoinbaseBech32() = %q, %v; want %q", got, err, want)
+	}
+	if back, err := common2.Bech32ToAddress(got); err != nil || back != addr {
+		t.Errorf("Bech32ToAddress(%q) = %x, %v; want %x", got, back, err, addr)
+	}
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	var enc struct {
+		MinerBech32 string `json:"minerBech32"`
+	}
+	if err := json.Unmarshal(b, &enc); err != nil || enc.MinerBech32 != want {
+		t.Errorf("MarshalJSON() minerBech32 = %q, %v; want %q", enc.MinerBech32, err, want)
+	}
+
+	zero, err := (&Header{Header: v3.NewHeader()}).CoinbaseBech32()
+	if err != nil {
+		t.Fatalf("CoinbaseBech32() of zero address error = %v", err)
+	}
+	if back, err := common2.Bech32ToAddress(zero); err != nil || back != (common.Address{}) {
+		t.Errorf("Bech32ToAddress(%q) = %x, %v; want zero address", zero, back, err)
+	}
+}
+
+func TestHeader_IsEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		h    *Header
+		want bool
+	}{
+		{"Nil", nil, true},
+		{"NilEmbedded", &Header{}, true},
+		{"DefaultV0", &Header{Header: v0.NewHeader()}, true},
+		{"DefaultV3", &Header{Header: v3.NewHeader()}, true},
+		{"Populated", (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(100)).ParentHash(common.HexToHash("0x1234")).Header(), false},
+		{"RootOnly", (&Header{Header: v3.NewHeader()}).With().
+			Root(common.HexToHash("0x5678")).Header(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.h.IsEmpty(); got != tt.want {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_DecodedShardState(t *testing.T) {
+	want := shard.State{
+		Epoch: big.NewInt(3),
+		Shards: []shard.Committee{{
+			ShardID: 1,
+			Slots: shard.SlotList{{
+				EcdsaAddress: common.HexToAddress("0x1234"),
+			}},
+		}},
+	}
+	encoded, err := shard.EncodeWrapper(want, true)
+	if err != nil {
+		t.Fatalf("cannot encode shard state: %v", err)
+	}
+	h := (&Header{Header: v3.NewHeader()}).With().ShardState(encoded).Header()
+	got, err := h.DecodedShardState()
+	if err != nil {
+		t.Fatalf("DecodedShardState() error = %v", err)
+	}
+	if got.Epoch.Cmp(want.Epoch) != 0 || len(got.Shards) != 1 ||
+		got.Shards[0].ShardID != 1 || len(got.Shards[0].Slots) != 1 ||
+		got.Shards[0].Slots[0].EcdsaAddress != want.Shards[0].Slots[0].EcdsaAddress {
+		t.Errorf("DecodedShardState() = %v, want %v", got, &want)
+	}
+
+	h.SetShardState(nil)
+	if _, err := h.DecodedShardState(); err != ErrNoShardState {
+		t.Errorf("DecodedShardState() error = %v, want %v", err, ErrNoShardState)
+	}
+
+	h.SetShardState([]byte{0xde, 0xad, 0xbe, 0xef})
+	if _, err := h.DecodedShardState(); err == nil || err == ErrNoShardState {
+		t.Errorf("DecodedShardState() error = %v, want decoding error", err)
+	}
+}
+
+func TestHeader_TrimShardState(t *testing.T) {
+	shardState := []byte{0xc0, 0x01, 0x02}
+	h := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).ShardState(shardState).Header()
+	hash := h.Hash()
+	trimmed := h.TrimShardState()
+	if len(trimmed.ShardState()) != 0 {
+		t.Errorf("trimmed ShardState() = %x, want empty", trimmed.ShardState())
+	}
+	if trimmed.Hash() == hash {
+		t.Errorf("trimmed Hash() = %x, same as original", trimmed.Hash())
+	}
+	if trimmed.Number().Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("trimmed Number() = %v, want 100", trimmed.Number())
+	}
+	if !bytes.Equal(h.ShardState(), shardState) || h.Hash() != hash {
+		t.Errorf("original header changed: shard state %x, hash %x", h.ShardState(), h.Hash())
+	}
+	if h.HashNoCache() != hash {
+		t.Errorf("original HashNoCache() = %x, want %x", h.HashNoCache(), hash)
+	}
+
+	if trimmed := (*Header)(nil).TrimShardState(); trimmed != nil {
+		t.Errorf("TrimShardState() of nil header = %v, want nil", trimmed)
+	}
+}
+
+func TestHeader_EpochTransition(t *testing.T) {
+	want := shard.State{
+		Epoch:  big.NewInt(4),
+		Shards: []shard.Committee{{ShardID: 0}},
+	}
+	encoded, err := shard.EncodeWrapper(want, true)
+	if err != nil {
+		t.Fatalf("cannot encode shard state: %v", err)
+	}
+
+	h := (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(100)).Header()
+	if isTransition, state, err := h.EpochTransition(); isTransition || state != nil || err != nil {
+		t.Errorf("EpochTransition() = %v, %v, %v; want false, nil, nil", isTransition, state, err)
+	}
+
+	h.SetShardState(encoded)
+	isTransition, state, err := h.EpochTransition()
+	if !isTransition || err != nil {
+		t.Fatalf("EpochTransition() = %v, %v, %v; want true, state, nil", isTransition, state, err)
+	}
+	if state.Epoch.Cmp(want.Epoch) != 0 || len(state.Shards) != 1 {
+		t.Errorf("EpochTransition() state = %v, want %v", state, &want)
+	}
+
+	h.SetShardState([]byte{0xde, 0xad, 0xbe, 0xef})
+	if isTransition, state, err := h.EpochTransition(); !isTransition || state != nil || err == nil {
+		t.Errorf("EpochTransition() = %v, %v, %v; want true, nil, decoding error", isTransition, state, err)
+	}
+}
+
+func TestHeader_Logger(t *testing.T) {
+	tests := []struct {
+		name       string
+		newHeader  func() blockif.Header
+		wantViewID bool
+	}{
+		{"v0", func() blockif.Header { return v0.NewHeader() }, true},
+		{"v3", func() blockif.Header { return v3.NewHeader() }, true},
+		{"Unregistered", func() blockif.Header { return unregisteredHeader{v3.NewHeader()} }, false},
+	}
+	for _, tt := range tests {
+		for _, lastBlock := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/lastBlock=%v", tt.name, lastBlock), func(t *testing.T) {
+				h := (&Header{Header: tt.newHeader()}).With().
+					ViewID(big.NewInt(42)).Epoch(big.NewInt(7)).Header()
+				if lastBlock {
+					h.SetShardState([]byte{0xc0})
+				}
+				var buf bytes.Buffer
+				logger := zerolog.New(&buf)
+				h.Logger(&logger).Info().Msg("test")
+				var got map[string]interface{}
+				if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+					t.Fatalf("cannot parse log output %q: %v", buf.String(), err)
+				}
+				if viewID, ok := got["blockViewID"]; ok != tt.wantViewID {
+					t.Errorf("blockViewID present = %v, want %v", ok, tt.wantViewID)
+				} else if ok && viewID != float64(42) {
+					t.Errorf("blockViewID = %v, want 42", viewID)
+				}
+				if got["epochLastBlock"] != lastBlock {
+					t.Errorf("epochLastBlock = %v, want %v", got["epochLastBlock"], lastBlock)
+				}
+			})
+		}
+	}
+}
+
+func TestHeader_DecodeRLP_UnknownVersion(t *testing.T) {
+	reg := taggedrlp.NewRegistry()
+	reg.MustRegister("v99", v3.NewHeader())
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, v3.NewHeader()); err != nil {
+		t.Fatalf("cannot encode header: %v", err)
+	}
+	err := rlp.DecodeBytes(buf.Bytes(), &Header{})
+	if !errors.Is(err, ErrUnknownHeaderVersion) {
+		t.Errorf("DecodeRLP() error = %v, want %v", err, ErrUnknownHeaderVersion)
+	}
+	var tagErr taggedrlp.UnsupportedTag
+	if !errors.As(err, &tagErr) || tagErr.Tag != "v99" {
+		t.Errorf("DecodeRLP() error = %v, want cause %v",
+			err, taggedrlp.UnsupportedTag{Tag: "v99"})
+	}
+
+	// Corrupt streams are not mistaken for unknown versions.
+	err = rlp.DecodeBytes([]byte{0xc1, 0xff}, &Header{})
+	if err == nil || errors.Is(err, ErrUnknownHeaderVersion) {
+		t.Errorf("DecodeRLP() error = %v, want non-version error", err)
+	}
+}
+
+func TestHeader_DecodeRLPAllowing(t *testing.T) {
+	allowed := []string{"v3", "v4"}
+	v3Header := (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(7)).Header()
+	b, err := v3Header.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	var got Header
+	if err := got.DecodeRLPAllowing(rlp.NewStream(bytes.NewReader(b), 0), allowed); err != nil {
+		t.Fatalf("DecodeRLPAllowing() error = %v", err)
+	}
+	if got.Hash() != v3Header.Hash() {
+		t.Errorf("DecodeRLPAllowing() got hash %x, want %x", got.Hash(), v3Header.Hash())
+	}
+
+	v1Header := (&Header{Header: v1.NewHeader()}).With().Number(big.NewInt(7)).Header()
+	b, err = v1Header.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	err = got.DecodeRLPAllowing(rlp.NewStream(bytes.NewReader(b), 0), allowed)
+	if errors.Cause(err) != ErrVersionNotAllowed {
+		t.Errorf("DecodeRLPAllowing() error = %v, want %v", err, ErrVersionNotAllowed)
+	}
+	if got.Version() != "v3" {
+		t.Errorf("DecodeRLPAllowing() changed header to version %s on error", got.Version())
+	}
+}
+
+func TestHeader_HashWith(t *testing.T) {
+	keccak := func(b []byte) common.Hash { return hash.Keccak256Hash(b) }
+	h := randomHeader(rand.New(rand.NewSource(1)))
+	got, err := h.HashWith(keccak)
+	if err != nil || got != h.Hash() {
+		t.Errorf("HashWith(Keccak256) = %x, %v; want %x", got, err, h.Hash())
+	}
+	alt, err := h.HashWith(func(b []byte) common.Hash { return sha256.Sum256(b) })
+	if err != nil || alt == h.Hash() {
+		t.Errorf("HashWith(SHA-256) = %x, %v; want a value other than %x", alt, err, h.Hash())
+	}
+	var nilHeader *Header
+	if _, err := nilHeader.HashWith(keccak); err != ErrHeaderIsNil {
+		t.Errorf("HashWith() error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
+
+func TestHeader_SealHash(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(100)).Header()
+	unsigned, err := h.SealHash()
+	if err != nil {
+		t.Fatalf("SealHash() error = %v", err)
+	}
+	if unsigned != h.Hash() {
+		t.Errorf("SealHash() = %x, want Hash() %x for unsigned header", unsigned, h.Hash())
+	}
+	h.SetLastCommitSignature([96]byte{1, 2, 3})
+	h.SetLastCommitBitmap([]byte{0xff})
+	signed, err := h.SealHash()
+	if err != nil {
+		t.Fatalf("SealHash() error = %v", err)
+	}
+	if signed != unsigned {
+		t.Errorf("SealHash() changed from %x to %x after signing", unsigned, signed)
+	}
+	if signed == h.Hash() {
+		t.Error("SealHash() equals Hash() of signed header")
+	}
+	if sig := h.LastCommitSignature(); sig[0] != 1 || len(h.LastCommitBitmap()) != 1 {
+		t.Error("SealHash() modified the header")
+	}
+}
+
+// randomHeader returns a header of a random version with random field values.
+func randomHeader(rnd *rand.Rand) *Header {
+	return randomHeaderOf(rnd, headerVersions[rnd.Intn(len(headerVersions))])
+}
+
+// randomHeaderOf returns a header of the given version with random field
+// values.
+func randomHeaderOf(rnd *rand.Rand, v headerVersion) *Header {
+	randomBytes := func(maxLen int) []byte {
+		b := make([]byte, rnd.Intn(maxLen+1))
+		rnd.Read(b)
+		return b
+	}
+	randomHash := func() (hash common.Hash) {
+		rnd.Read(hash[:])
+		return hash
+	}
+	randomBig := func() *big.Int {
+		return new(big.Int).SetBytes(randomBytes(16))
+	}
+	var sig [96]byte
+	rnd.Read(sig[:])
+	h := (&Header{Header: v.factory()}).With().
+		ParentHash(randomHash()).
+		Root(randomHash()).
+		TxHash(randomHash()).
+		ReceiptHash(randomHash()).
+		Number(randomBig()).
+		GasLimit(rnd.Uint64()).
+		GasUsed(rnd.Uint64()).
+		Time(randomBig()).
+		UncheckedExtra(randomBytes(64)).
+		ViewID(randomBig()).
+		Epoch(randomBig()).
+		ShardID(rnd.Uint32()).
+		LastCommitSignature(sig).
+		LastCommitBitmap(randomBytes(16)).
+		ShardState(randomBytes(32)).
+		Header()
+	if v.features.crossShardReceipts {
+		h.SetOutgoingReceiptHash(randomHash())
+		h.SetIncomingReceiptHash(randomHash())
+	}
+	if v.features.shardStateRoot {
+		h.SetShardStateHash(randomHash())
+	}
+	if v.features.randomness {
+		h.SetVrf(randomBytes(32))
+		h.SetVdf(randomBytes(32))
+	}
+	if v.features.crossLinks {
+		h.SetCrossLinks(randomBytes(32))
+	}
+	if v.features.slashes {
+		h.SetSlashes(randomBytes(32))
+	}
+	if v.features.baseFee {
+		h.SetBaseFee(randomBig())
+	}
+	return h
+}
+
+func TestHeaderEncodeStability(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, v := range headerVersions {
+		t.Run(v.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				h := randomHeaderOf(rnd, v)
+				// Keep the header valid: gas used within the gas limit.
+				if h.GasUsed() > h.GasLimit() {
+					h.SetGasUsed(h.GasLimit())
+				}
+				b, err := h.Bytes()
+				if err != nil {
+					t.Fatalf("#%d: Bytes() error = %v", i, err)
+				}
+				decoded, err := HeaderFromBytes(b)
+				if err != nil {
+					t.Fatalf("#%d: HeaderFromBytes() error = %v", i, err)
+				}
+				if decoded.Version() != v.name {
+					t.Fatalf("#%d: decoded version = %s, want %s", i, decoded.Version(), v.name)
+				}
+				reencoded, err := decoded.Bytes()
+				if err != nil {
+					t.Fatalf("#%d: re-encoding Bytes() error = %v", i, err)
+				}
+				if !bytes.Equal(reencoded, b) {
+					t.Fatalf("#%d: re-encoding differs:\n got %x\nwant %x", i, reencoded, b)
+				}
+				if decoded.Hash() != h.Hash() {
+					t.Fatalf("#%d: decoded Hash() = %x, want %x", i, decoded.Hash(), h.Hash())
+				}
+			}
+		})
+	}
+}
+
+func TestHeader_CloneViaRLP(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		h := randomHeader(rnd)
+		clone, err := h.CloneViaRLP()
+		if err != nil {
+			t.Fatalf("#%d: CloneViaRLP() error = %v", i, err)
+		}
+		cpy := h.Copy()
+		if cpy.Hash() != clone.Hash() {
+			t.Fatalf("#%d (%s): Copy() hash %x != CloneViaRLP() hash %x",
+				i, h.Version(), cpy.Hash(), clone.Hash())
+		}
+		if !cpy.Equal(clone) {
+			t.Fatalf("#%d (%s): Copy() and CloneViaRLP() differ in %s",
+				i, h.Version(), firstDifferentField(cpy, clone))
+		}
+	}
+}
+
+func BenchmarkHeader_Copy(b *testing.B) {
+	h := randomHeader(rand.New(rand.NewSource(1)))
+	for i := 0; i < b.N; i++ {
+		h.Copy()
+	}
+}
+
+func BenchmarkHeader_CloneViaRLP(b *testing.B) {
+	h := randomHeader(rand.New(rand.NewSource(1)))
+	for i := 0; i < b.N; i++ {
+		if _, err := h.CloneViaRLP(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestHeaderFromHex(t *testing.T) {
+	for _, hif := range []blockif.Header{
+		v0.NewHeader(), v1.NewHeader(), v2.NewHeader(), v3.NewHeader(),
+		v4.NewHeader(),
+	} {
+		t.Run(headerVersionName(hif), func(t *testing.T) {
+			h := (&Header{Header: hif}).With().
+				Number(big.NewInt(100)).Extra([]byte("extra")).Header()
+			s, err := h.Hex()
+			if err != nil {
+				t.Fatalf("Hex() error = %v", err)
+			}
+			if !strings.HasPrefix(s, "0x") {
+				t.Errorf("Hex() = %q, want 0x prefix", s)
+			}
+			for _, input := range []string{s, s[2:]} {
+				got, err := HeaderFromHex(input)
+				if err != nil {
+					t.Fatalf("HeaderFromHex() error = %v", err)
+				}
+				if reflect.TypeOf(got.Header) != reflect.TypeOf(hif) || !got.Equal(h) {
+					t.Errorf("HeaderFromHex() = %v, want %v", got, h)
+				}
+			}
+		})
+	}
+}
+
+func TestHeaderFromHex_Invalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"NotHex":  "0xzz",
+		"OddLen":  "0x123",
+		"NotRLP":  "0xc1",
+		"Garbage": "deadbeef",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := HeaderFromHex(input); err == nil {
+				t.Error("HeaderFromHex() expected error")
+			}
+		})
+	}
+}
+
+func TestHeader_GasUtilization(t *testing.T) {
+	tests := []struct {
+		name              string
+		gasLimit, gasUsed uint64
+		want              float64
+	}{
+		{"Empty", 1000, 0, 0},
+		{"Half", 1000, 500, 0.5},
+		{"Full", 1000, 1000, 1},
+		{"ZeroLimit", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := (&Header{Header: v3.NewHeader()}).With().
+				GasLimit(tt.gasLimit).GasUsed(tt.gasUsed).Header()
+			if got := h.GasUtilization(); got != tt.want {
+				t.Errorf("GasUtilization() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// binaryCache is a minimal cache that stores values in their binary form, as
+// caches built on encoding.BinaryMarshaler do.
+type binaryCache map[string][]byte
+
+func (c binaryCache) Set(key string, value encoding.BinaryMarshaler) error {
+	b, err := value.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	c[key] = b
+	return nil
+}
+
+func (c binaryCache) Get(key string, value encoding.BinaryUnmarshaler) error {
+	return value.UnmarshalBinary(c[key])
+}
+
+func TestHeader_MarshalBinary(t *testing.T) {
+	cache := binaryCache{}
+	for _, hif := range []blockif.Header{
+		v0.NewHeader(), v1.NewHeader(), v2.NewHeader(), v3.NewHeader(),
+		v4.NewHeader(),
+	} {
+		t.Run(headerVersionName(hif), func(t *testing.T) {
+			h := (&Header{Header: hif}).With().
+				Number(big.NewInt(100)).Extra([]byte("extra")).Header()
+			if err := cache.Set("header", h); err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			got := &Header{Header: v0.NewHeader()}
+			got.Hash() // populate the cache, which UnmarshalBinary must reset
+			if err := cache.Get("header", got); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if reflect.TypeOf(got.Header) != reflect.TypeOf(hif) || !got.Equal(h) {
+				t.Errorf("UnmarshalBinary() = %v, want %v", got, h)
+			}
+			if got.Hash() != h.Hash() {
+				t.Errorf("UnmarshalBinary() got hash %x, want %x", got.Hash(), h.Hash())
+			}
+		})
+	}
+	if err := (&Header{}).UnmarshalBinary([]byte{0xde, 0xad}); err == nil {
+		t.Error("UnmarshalBinary() expected error for garbage")
+	}
+}
+
+// panickingHeader is a header whose RLP decoder panics.
+type panickingHeader struct {
+	*v3.Header
+}
+
+func (h *panickingHeader) DecodeRLP(*rlp.Stream) error {
+	panic("malformed field")
+}
+
+func TestHeader_DecodeRLP_RecoversPanic(t *testing.T) {
+	reg := taggedrlp.NewRegistry()
+	reg.MustRegister("v3", &panickingHeader{})
+	b, err := rlp.EncodeToBytes(&Header{Header: v3.NewHeader()})
+	if err != nil {
+		t.Fatalf("cannot encode header: %v", err)
+	}
+	s := rlp.NewStream(bytes.NewReader(b), uint64(len(b)))
+	err = (&Header{}).DecodeRLPWithRegistry(s, reg)
+	if err == nil {
+		t.Fatal("DecodeRLPWithRegistry() expected error")
+	}
+	if msg := err.Error(); !strings.Contains(msg, "version v3") ||
+		!strings.Contains(msg, "malformed field") {
+		t.Errorf("DecodeRLPWithRegistry() error %q does not name version and cause", msg)
+	}
+}
+
+func TestHeader_TimeAsTime(t *testing.T) {
+	beyondInt64, _ := new(big.Int).SetString("100000000000000000000", 10)
+	tests := []struct {
+		name string
+		time *big.Int
+		want time.Time
+	}{
+		{"Normal", big.NewInt(1600000000), time.Unix(1600000000, 0).UTC()},
+		{"Zero", big.NewInt(0), time.Unix(0, 0).UTC()},
+		{"BeyondInt64", beyondInt64, MaxHeaderTime},
+		{"BeyondMax", big.NewInt(math.MaxInt64), MaxHeaderTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := (&Header{Header: v3.NewHeader()}).With().Time(tt.time).Header()
+			if got := h.TimeAsTime(); !got.Equal(tt.want) {
+				t.Errorf("TimeAsTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if MaxHeaderTime.Before(time.Unix(1600000000, 0)) {
+		t.Errorf("MaxHeaderTime = %v overflowed", MaxHeaderTime)
+	}
+}
+
+func TestHeader_Age(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().Time(big.NewInt(1600000000)).Header()
+	now := time.Unix(1600000090, 0)
+	if got := h.Age(now); got != 90*time.Second {
+		t.Errorf("Age() = %v, want 1m30s", got)
+	}
+}
+
+func TestAllVersionsImplementHeader(t *testing.T) {
+	for _, v := range headerVersions {
+		t.Run(v.name, func(t *testing.T) {
+			// Round-trip through HeaderRegistry so that the object checked is
+			// the one constructed by the registry factory for the tag.
+			var buf bytes.Buffer
+			if err := HeaderRegistry.Encode(&buf, v.factory()); err != nil {
+				t.Fatalf("cannot encode %s header: %v", v.name, err)
+			}
+			decoded, err := HeaderRegistry.Decode(rlp.NewStream(&buf, 0))
+			if err != nil {
+				t.Fatalf("cannot decode %s header: %v", v.name, err)
+			}
+			if _, ok := decoded.(blockif.Header); !ok {
+				t.Errorf("registry factory for %s made %T, which does not implement blockif.Header",
+					v.name, decoded)
+			}
+		})
+	}
+}
+
+func TestHeader_ShardStateBytesHash(t *testing.T) {
+	withState := func(shardState []byte) *Header {
+		return (&Header{Header: v2.NewHeader()}).With().
+			ShardStateHash(common.HexToHash("0x1234")).
+			ShardState(shardState).
+			Header()
+	}
+	if got := withState(nil).ShardStateBytesHash(); got != (common.Hash{}) {
+		t.Errorf("ShardStateBytesHash() of empty shard state = %x, want zero", got)
+	}
+	x, y := withState([]byte{0xc1, 0x01}), withState([]byte{0xc1, 0x01})
+	if x.ShardStateBytesHash() != y.ShardStateBytesHash() {
+		t.Errorf("ShardStateBytesHash() of identical states differ: %x != %x",
+			x.ShardStateBytesHash(), y.ShardStateBytesHash())
+	}
+	if x.ShardStateBytesHash() == (common.Hash{}) ||
+		x.ShardStateBytesHash() == x.ShardStateHash() {
+		t.Errorf("ShardStateBytesHash() = %x, want keccak of shard state", x.ShardStateBytesHash())
+	}
+	if z := withState([]byte{0xc1, 0x02}); x.ShardStateBytesHash() == z.ShardStateBytesHash() {
+		t.Error("ShardStateBytesHash() of different states are equal")
+	}
+	if got, want := x.ShardStateHash(), common.HexToHash("0x1234"); got != want {
+		t.Errorf("ShardStateHash() = %x, want stored root %x", got, want)
+	}
+}
+
+func TestHeader_IsFirstBlockInEpoch(t *testing.T) {
+	header := func(number, epoch int64) *Header {
+		return (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(number)).
+			ParentHash(common.BigToHash(big.NewInt(number - 1))).
+			Epoch(big.NewInt(epoch)).
+			Header()
+	}
+	genesis := (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(0)).Header()
+	tests := []struct {
+		name      string
+		h, parent *Header
+		want      bool
+	}{
+		{"MidEpoch", header(101, 7), header(100, 7), false},
+		{"Boundary", header(101, 8), header(100, 7), true},
+		{"Genesis", genesis, header(0, 0), true},
+		{"NilParent", header(101, 7), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.h.IsFirstBlockInEpoch(tt.parent); got != tt.want {
+				t.Errorf("IsFirstBlockInEpoch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// nilEpochHeader is a header whose Epoch getter returns nil.
+type nilEpochHeader struct {
+	*v3.Header
+}
+
+func (nilEpochHeader) Epoch() *big.Int { return nil }
+
+func TestHeader_EpochNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		h    *Header
+		want uint64
+	}{
+		{"Set", (&Header{Header: v3.NewHeader()}).With().Epoch(big.NewInt(7)).Header(), 7},
+		{"NilEpoch", &Header{Header: nilEpochHeader{v3.NewHeader()}}, 0},
+		{"ZeroValueHeader", &Header{Header: &v3.Header{}}, 0},
+		{"NilEmbedded", &Header{}, 0},
+		{"Nil", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.h.EpochNumber(); got != tt.want {
+				t.Errorf("EpochNumber() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_NumberU64(t *testing.T) {
+	huge := new(big.Int).Lsh(big.NewInt(1), 64)
+	tests := []struct {
+		name string
+		h    *Header
+		want uint64
+		err  error
+	}{
+		{"Normal", (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(100)).Header(), 100, nil},
+		{"MaxUint64", (&Header{Header: v3.NewHeader()}).With().
+			Number(new(big.Int).SetUint64(math.MaxUint64)).Header(), math.MaxUint64, nil},
+		{"Huge", (&Header{Header: v3.NewHeader()}).With().Number(huge).Header(), 0, ErrNumberOverflow},
+		{"Negative", (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(-1)).Header(), 0, ErrNumberOverflow},
+		{"Unset", &Header{Header: &v3.Header{}}, 0, ErrNumberUnset},
+		{"Nil", nil, 0, ErrHeaderIsNil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.h.NumberU64()
+			if got != tt.want || errors.Cause(err) != tt.err {
+				t.Errorf("NumberU64() = %d, %v; want %d, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+	t.Run("Must", func(t *testing.T) {
+		if got := tests[0].h.MustNumberU64(); got != 100 {
+			t.Errorf("MustNumberU64() = %d, want 100", got)
+		}
+		defer func() {
+			if recover() == nil {
+				t.Error("MustNumberU64() of huge number did not panic")
+			}
+		}()
+		tests[2].h.MustNumberU64()
+	})
+	t.Run("Logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := zerolog.New(&buf)
+		tests[2].h.Logger(&logger).Info().Msg("test")
+		var got map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("cannot parse log output %q: %v", buf.String(), err)
+		}
+		if got["blockNumber"] != float64(0) || got["blockNumberError"] == nil {
+			t.Errorf("blockNumber = %v, blockNumberError = %v; want 0 and an error",
+				got["blockNumber"], got["blockNumberError"])
+		}
+	})
+}
+
+func TestHeader_Logger_NilEpoch(t *testing.T) {
+	for _, hif := range []blockif.Header{nilEpochHeader{v3.NewHeader()}, &v3.Header{}} {
+		t.Run(fmt.Sprintf("%T", hif), func(t *testing.T) {
+			h := &Header{Header: hif}
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+			h.Logger(&logger).Info().Msg("test")
+			var got map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("cannot parse log output %q: %v", buf.String(), err)
+			}
+			if got["blockEpoch"] != float64(0) {
+				t.Errorf("blockEpoch = %v, want 0", got["blockEpoch"])
+			}
+		})
+	}
+}
+
+func TestHeaderVersionOf(t *testing.T) {
+	for _, v := range headerVersions {
+		hif := v.factory()
+		got, ok := headerVersionOf(hif)
+		if !ok || got.name != v.name {
+			t.Errorf("headerVersionOf(%T) = %v, %v; want %v", hif, got.name, ok, v.name)
+		}
+		if allocs := testing.AllocsPerRun(10, func() { headerVersionOf(hif) }); allocs != 0 {
+			t.Errorf("headerVersionOf(%T) allocates %v times, want 0", hif, allocs)
+		}
+	}
+	if _, ok := headerVersionOf(unregisteredHeader{v3.NewHeader()}); ok {
+		t.Error("headerVersionOf() of unregistered header type succeeded")
+	}
+	if _, ok := headerVersionOf(nil); ok {
+		t.Error("headerVersionOf(nil) succeeded")
+	}
+}
+
+func TestSupportedHeaderVersions(t *testing.T) {
+	want := []string{"legacy", "v1", "v2", "v3", "v4"}
+	if got := SupportedHeaderVersions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SupportedHeaderVersions() = %v, want %v", got, want)
+	}
+	r := newHeaderRegistry()
+	if err := r.register("v99", func() blockif.Header { return &customHeader{v3.NewHeader()} }); err != nil {
+		t.Fatalf("register() error = %v", err)
+	}
+	want = append(want, "v99")
+	if got := r.versionNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("versionNames() = %v, want %v", got, want)
+	}
+	if got := SupportedHeaderVersions(); len(got) != len(want)-1 {
+		t.Errorf("SupportedHeaderVersions() = %v after registering in another registry", got)
+	}
+}
+
+func TestHeader_WithDefaults(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		h := (&Header{Header: &v3.Header{}}).WithDefaults()
+		for name, x := range map[string]*big.Int{
+			"Number": h.Number(), "Epoch": h.Epoch(), "Time": h.Time(), "ViewID": h.ViewID(),
+		} {
+			if x.Sign() != 0 {
+				t.Errorf("%s() = %v, want 0", name, x)
+			}
+		}
+		for name, b := range map[string][]byte{
+			"Extra": h.Extra(), "LastCommitBitmap": h.LastCommitBitmap(),
+			"ShardState": h.ShardState(), "Vrf": h.Vrf(), "Vdf": h.Vdf(),
+			"CrossLinks": h.CrossLinks(), "Slashes": h.Slashes(),
+		} {
+			if b == nil || len(b) != 0 {
+				t.Errorf("%s() = %#v, want empty slice", name, b)
+			}
+		}
+	})
+	t.Run("Set", func(t *testing.T) {
+		h := (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(100)).
+			Epoch(big.NewInt(7)).
+			Time(big.NewInt(1600000000)).
+			Extra([]byte("extra")).
+			ShardState([]byte{0xc0}).
+			Header()
+		want := h.Copy()
+		if got := h.WithDefaults(); got != h {
+			t.Errorf("WithDefaults() = %p, want receiver %p", got, h)
+		}
+		if !h.Equal(want) || h.Hash() != want.Hash() {
+			t.Errorf("WithDefaults() changed set fields: %v, want %v", h, want)
+		}
+	})
+	t.Run("Nil", func(t *testing.T) {
+		var h *Header
+		if got := h.WithDefaults(); got != nil {
+			t.Errorf("WithDefaults() = %v, want nil", got)
+		}
+	})
+}
+
+func TestHeader_VRFVDFProof(t *testing.T) {
+	for _, v := range headerVersions {
+		t.Run(v.name, func(t *testing.T) {
+			h := &Header{Header: v.factory()}
+			if v.features.randomness {
+				h.SetVrf([]byte("vrf"))
+				h.SetVdf([]byte("vdf"))
+			}
+			vrf, ok := h.VRFProof()
+			if ok != v.features.randomness {
+				t.Errorf("VRFProof() supported = %v, want %v", ok, v.features.randomness)
+			}
+			vdf, ok := h.VDFProof()
+			if ok != v.features.randomness {
+				t.Errorf("VDFProof() supported = %v, want %v", ok, v.features.randomness)
+			}
+			if v.features.randomness {
+				if string(vrf) != "vrf" || string(vdf) != "vdf" {
+					t.Errorf("VRFProof(), VDFProof() = %q, %q; want \"vrf\", \"vdf\"", vrf, vdf)
+				}
+			} else if vrf != nil || vdf != nil {
+				t.Errorf("VRFProof(), VDFProof() = %q, %q; want nil, nil", vrf, vdf)
+			}
+		})
+	}
+	if _, ok := (&Header{Header: v0.NewHeader()}).VRFProof(); ok {
+		t.Error("VRFProof() supported on legacy header")
+	}
+}
@@ -0,0 +1,37 @@
+package block
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// WalkAncestors walks back from the header by following parent hashes, at
+// most n times, using lookup to find the header with a given hash.  The walk
+// stops early at a genesis header, or when lookup returns a nil header, e.g.
+// because the parent has been pruned or not yet synced.
+//
+// WalkAncestors returns the walked headers, starting with h itself and going
+// back in time.  If lookup fails, it returns the headers walked so far along
+// with the error.
+func (h *Header) WalkAncestors(
+	lookup func(common.Hash) (*Header, error), n int,
+) ([]*Header, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	headers := []*Header{h}
+	for cur := h; len(headers) <= n && !cur.IsGenesis(); {
+		parentHash := cur.ParentHash()
+		parent, err := lookup(parentHash)
+		if err != nil {
+			return headers, errors.Wrapf(err,
+				"cannot look up parent %s of block %v", parentHash.Hex(), cur.Number())
+		}
+		if isNilHeader(parent) {
+			break
+		}
+		headers = append(headers, parent)
+		cur = parent
+	}
+	return headers, nil
+}
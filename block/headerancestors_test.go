@@ -0,0 +1,80 @@
+package block
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_WalkAncestors(t *testing.T) {
+	// chain[i] is block i; block 0 is genesis.
+	chain := []*Header{(&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(0)).Header()}
+	for len(chain) < 5 {
+		chain = append(chain, chain[len(chain)-1].NewChild().Header())
+	}
+	byHash := make(map[common.Hash]*Header)
+	for _, h := range chain {
+		byHash[h.Hash()] = h
+	}
+	mapLookup := func(hash common.Hash) (*Header, error) { return byHash[hash], nil }
+	errLookup := errors.New("lookup failed")
+
+	tests := []struct {
+		name    string
+		start   int
+		n       int
+		lookup  func(common.Hash) (*Header, error)
+		want    []int
+		wantErr error
+	}{
+		{"Full", 4, 2, mapLookup, []int{4, 3, 2}, nil},
+		{"Zero", 4, 0, mapLookup, []int{4}, nil},
+		{"Genesis", 2, 10, mapLookup, []int{2, 1, 0}, nil},
+		{"StartAtGenesis", 0, 10, mapLookup, []int{0}, nil},
+		{
+			"Miss", 4, 10,
+			func(hash common.Hash) (*Header, error) {
+				if hash == chain[2].Hash() {
+					return nil, nil
+				}
+				return byHash[hash], nil
+			},
+			[]int{4, 3}, nil,
+		},
+		{
+			"Error", 4, 10,
+			func(hash common.Hash) (*Header, error) {
+				if hash == chain[2].Hash() {
+					return nil, errLookup
+				}
+				return byHash[hash], nil
+			},
+			[]int{4, 3}, errLookup,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chain[tt.start].WalkAncestors(tt.lookup, tt.n)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("WalkAncestors() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("WalkAncestors() returned %d headers, want %d", len(got), len(tt.want))
+			}
+			for i, number := range tt.want {
+				if got[i] != chain[number] {
+					t.Errorf("WalkAncestors()[%d] = block %v, want block %d",
+						i, got[i].Number(), number)
+				}
+			}
+		})
+	}
+
+	if _, err := (*Header)(nil).WalkAncestors(mapLookup, 1); err != ErrHeaderIsNil {
+		t.Errorf("WalkAncestors() on nil header error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
@@ -0,0 +1,77 @@
+package block
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// HashHeaders computes the hashes of the given headers concurrently, using up
+// to GOMAXPROCS workers.  The i-th returned hash is that of headers[i].
+//
+// Unlike Hash, it reports encoding errors: it stops at the first header that
+// cannot be encoded and returns an error naming the index of that header.
+// The computed hashes are cached in the headers as by Hash.
+func HashHeaders(headers []*Header) ([]common.Hash, error) {
+	hashes := make([]common.Hash, len(headers))
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(headers) {
+		workers = len(headers)
+	}
+	var (
+		next     int64 = -1
+		failed   int32
+		errMu    sync.Mutex
+		errIndex = len(headers)
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&failed) == 0 {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(headers) {
+					return
+				}
+				h, err := hashHeader(headers[i])
+				if err != nil {
+					atomic.StoreInt32(&failed, 1)
+					errMu.Lock()
+					if i < errIndex {
+						errIndex, firstErr = i, errors.Wrapf(err, "cannot hash header #%d", i)
+					}
+					errMu.Unlock()
+					return
+				}
+				hashes[i] = h
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return hashes, nil
+}
+
+// hashHeader returns the hash of the given header, or the error encountered
+// while encoding it.  It uses and populates the hash cache.
+func hashHeader(h *Header) (common.Hash, error) {
+	if isNilHeader(h) {
+		return common.Hash{}, ErrHeaderIsNil
+	}
+	if cached, _ := h.cachedHash.Load().(*common.Hash); cached != nil {
+		return *cached, nil
+	}
+	hash, err := hashHeaderEncoding(h)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	h.cachedHash.Store(&hash)
+	return hash, nil
+}
@@ -0,0 +1,77 @@
+package block
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+// makeHeaderChain returns n headers of alternating versions.
+func makeHeaderChain(n int) []*Header {
+	headers := make([]*Header, n)
+	for i := range headers {
+		h := &Header{Header: v3.NewHeader()}
+		if i%2 == 1 {
+			h = &Header{Header: v0.NewHeader()}
+		}
+		headers[i] = h.With().Number(big.NewInt(int64(i))).Header()
+	}
+	return headers
+}
+
+func TestHashHeaders(t *testing.T) {
+	headers := makeHeaderChain(100)
+	got, err := HashHeaders(headers)
+	if err != nil {
+		t.Fatalf("HashHeaders() error = %v", err)
+	}
+	if len(got) != len(headers) {
+		t.Fatalf("HashHeaders() returned %d hashes, want %d", len(got), len(headers))
+	}
+	for i, h := range headers {
+		if want := h.HashNoCache(); got[i] != want {
+			t.Errorf("HashHeaders()[%d] = %x, want %x", i, got[i], want)
+		}
+	}
+}
+
+func TestHashHeaders_Error(t *testing.T) {
+	headers := makeHeaderChain(10)
+	headers[7] = nil
+	_, err := HashHeaders(headers)
+	if errors.Cause(err) != ErrHeaderIsNil {
+		t.Fatalf("HashHeaders() error = %v, want %v", err, ErrHeaderIsNil)
+	}
+	if want := "cannot hash header #7"; !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("HashHeaders() error = %q, want index 7", err)
+	}
+}
+
+func BenchmarkHashHeaders(b *testing.B) {
+	headers := makeHeaderChain(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, h := range headers {
+			h.invalidateHash()
+		}
+		if _, err := HashHeaders(headers); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHashHeaders_Sequential(b *testing.B) {
+	headers := makeHeaderChain(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, h := range headers {
+			h.invalidateHash()
+			h.Hash()
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package block
+
+import "math/bits"
+
+// LastCommitBitmapChecked is like LastCommitBitmap, but also returns whether
+// the header carries the bitmap.  All header versions carry the bitmap, so
+// only a nil header returns (nil, false); the flag keeps this accessor in line
+// with those of optional fields such as CrossLinksChecked.
+//
+// The returned slice is a copy; the caller may do anything with it.
+func (h *Header) LastCommitBitmapChecked() ([]byte, bool) {
+	if isNilHeader(h) {
+		return nil, false
+	}
+	return h.Header.LastCommitBitmap(), true
+}
+
+// SignerCount returns the number of validators that signed the previous
+// block, i.e. the number of bits set in the last commit bitmap, and whether
+// the header carries the bitmap.
+func (h *Header) SignerCount() (int, bool) {
+	bitmap, ok := h.LastCommitBitmapChecked()
+	if !ok {
+		return 0, false
+	}
+	count := 0
+	for _, b := range bitmap {
+		count += bits.OnesCount8(b)
+	}
+	return count, true
+}
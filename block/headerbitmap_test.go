@@ -0,0 +1,45 @@
+package block
+
+import (
+	"bytes"
+	"testing"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_SignerCount(t *testing.T) {
+	bitmap := []byte{0xff, 0x00, 0x81, 0x0f}
+	for _, h := range []*Header{
+		{Header: v0.NewHeader()},
+		{Header: v3.NewHeader()},
+	} {
+		h.SetLastCommitBitmap(bitmap)
+		got, ok := h.LastCommitBitmapChecked()
+		if !ok || !bytes.Equal(got, bitmap) {
+			t.Errorf("%s: LastCommitBitmapChecked() = %x, %v; want %x, true",
+				h.Version(), got, ok, bitmap)
+		}
+		if n, ok := h.SignerCount(); !ok || n != 14 {
+			t.Errorf("%s: SignerCount() = %d, %v; want 14, true",
+				h.Version(), n, ok)
+		}
+	}
+	if n, ok := (&Header{Header: v3.NewHeader()}).SignerCount(); !ok || n != 0 {
+		t.Errorf("empty bitmap: SignerCount() = %d, %v; want 0, true", n, ok)
+	}
+}
+
+func TestHeader_SignerCount_Unsupported(t *testing.T) {
+	for name, h := range map[string]*Header{
+		"nil":          nil,
+		"nil embedded": {},
+	} {
+		if got, ok := h.LastCommitBitmapChecked(); ok || got != nil {
+			t.Errorf("%s: LastCommitBitmapChecked() = %x, %v; want nil, false", name, got, ok)
+		}
+		if n, ok := h.SignerCount(); ok || n != 0 {
+			t.Errorf("%s: SignerCount() = %d, %v; want 0, false", name, n, ok)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package block
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BloomContains returns whether the log bloom filter of the header may
+// contain the given log topic.  Bloom filters have false positives but no
+// false negatives: false means that no log of the block has the topic, while
+// true means only that one might, so use it to skip blocks before loading
+// their receipts, not as a definitive answer.
+func (h *Header) BloomContains(topic common.Hash) bool {
+	if isNilHeader(h) {
+		return false
+	}
+	return types.BloomLookup(h.Bloom(), topic)
+}
+
+// BloomContainsAddress is like BloomContains, but for a log emitter address.
+func (h *Header) BloomContainsAddress(addr common.Address) bool {
+	if isNilHeader(h) {
+		return false
+	}
+	return types.BloomLookup(h.Bloom(), addr)
+}
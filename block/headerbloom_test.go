@@ -0,0 +1,39 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_BloomContains(t *testing.T) {
+	addr := common.HexToAddress("0x1234")
+	topics := []common.Hash{common.HexToHash("0xaa"), common.HexToHash("0xbb")}
+	bloom := types.BytesToBloom(types.LogsBloom([]*types.Log{
+		{Address: addr, Topics: topics},
+	}).Bytes())
+	h := (&Header{Header: v3.NewHeader()}).With().Bloom(bloom).Header()
+	for _, topic := range topics {
+		if !h.BloomContains(topic) {
+			t.Errorf("BloomContains(%x) = false for included topic", topic)
+		}
+	}
+	if !h.BloomContainsAddress(addr) {
+		t.Errorf("BloomContainsAddress(%x) = false for included address", addr)
+	}
+	// Excluded items could be false positives in general, but these do not
+	// collide with the included ones.
+	if excluded := common.HexToHash("0xcc"); h.BloomContains(excluded) {
+		t.Errorf("BloomContains(%x) = true for excluded topic", excluded)
+	}
+	if excluded := common.HexToAddress("0x5678"); h.BloomContainsAddress(excluded) {
+		t.Errorf("BloomContainsAddress(%x) = true for excluded address", excluded)
+	}
+	var empty *Header
+	if empty.BloomContains(topics[0]) || empty.BloomContainsAddress(addr) {
+		t.Error("nil header bloom contains an item")
+	}
+}
@@ -0,0 +1,48 @@
+package block
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// HeaderCache is a bounded, least-recently-used cache of headers keyed by
+// their hashes.  It is safe for concurrent use.
+//
+// The cache stores and returns copies of headers, so callers may freely
+// modify the headers they add or get without affecting the cached entries.
+type HeaderCache struct {
+	cache *lru.Cache
+}
+
+// NewHeaderCache returns a new header cache holding up to size headers.
+func NewHeaderCache(size int) (*HeaderCache, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &HeaderCache{cache: cache}, nil
+}
+
+// Get returns a copy of the cached header with the given hash, if any.
+func (c *HeaderCache) Get(hash common.Hash) (*Header, bool) {
+	value, ok := c.cache.Get(hash)
+	if !ok {
+		return nil, false
+	}
+	return value.(*Header).Copy(), true
+}
+
+// Add caches a copy of the given header under its hash, evicting the least
+// recently used header if the cache is full.
+func (c *HeaderCache) Add(h *Header) {
+	if isNilHeader(h) {
+		return
+	}
+	cpy := h.Copy()
+	c.cache.Add(cpy.Hash(), cpy)
+}
+
+// Len returns the number of cached headers.
+func (c *HeaderCache) Len() int {
+	return c.cache.Len()
+}
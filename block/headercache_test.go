@@ -0,0 +1,89 @@
+package block
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeaderCache_Eviction(t *testing.T) {
+	c, err := NewHeaderCache(2)
+	if err != nil {
+		t.Fatalf("NewHeaderCache() error = %v", err)
+	}
+	headers := makeHeaderChain(3)
+	c.Add(headers[0])
+	c.Add(headers[1])
+	if _, ok := c.Get(headers[0].Hash()); !ok {
+		t.Fatal("Get() missed header #0")
+	}
+	// Header #1 is now the least recently used one.
+	c.Add(headers[2])
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+	if _, ok := c.Get(headers[1].Hash()); ok {
+		t.Error("Get() found evicted header #1")
+	}
+	for _, i := range []int{0, 2} {
+		if got, ok := c.Get(headers[i].Hash()); !ok || !got.Equal(headers[i]) {
+			t.Errorf("Get() header #%d = %v, %v", i, got, ok)
+		}
+	}
+}
+
+func TestHeaderCache_CopyIsolation(t *testing.T) {
+	c, err := NewHeaderCache(1)
+	if err != nil {
+		t.Fatalf("NewHeaderCache() error = %v", err)
+	}
+	h := (&Header{Header: v3.NewHeader()}).With().Extra([]byte("cached")).Header()
+	hash := h.Hash()
+	c.Add(h)
+	h.SetExtra([]byte("modified after add"))
+	got, ok := c.Get(hash)
+	if !ok {
+		t.Fatal("Get() missed header")
+	}
+	if string(got.Extra()) != "cached" {
+		t.Errorf("Get() extra = %q, want %q", got.Extra(), "cached")
+	}
+	got.SetExtra([]byte("modified after get"))
+	if again, _ := c.Get(hash); string(again.Extra()) != "cached" {
+		t.Errorf("Get() extra = %q, want %q", again.Extra(), "cached")
+	}
+}
+
+func TestHeaderCache_Concurrent(t *testing.T) {
+	c, err := NewHeaderCache(16)
+	if err != nil {
+		t.Fatalf("NewHeaderCache() error = %v", err)
+	}
+	headers := makeHeaderChain(32)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i, h := range headers {
+				if (i+w)%2 == 0 {
+					c.Add(h)
+				} else if got, ok := c.Get(h.Hash()); ok && got.Number().Cmp(big.NewInt(int64(i))) != 0 {
+					t.Errorf("Get() header #%d has number %v", i, got.Number())
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if c.Len() > 16 {
+		t.Errorf("Len() = %d, want at most 16", c.Len())
+	}
+}
+
+func TestNewHeaderCache_InvalidSize(t *testing.T) {
+	if _, err := NewHeaderCache(0); err == nil {
+		t.Error("NewHeaderCache(0) expected error")
+	}
+}
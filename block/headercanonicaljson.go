@@ -0,0 +1,39 @@
+package block
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// CanonicalJSON returns the JSON form of the header, with the same fields as
+// MarshalJSON, in a canonical form suitable for content addressing, in the
+// style of RFC 8785: object keys are sorted, there is no insignificant
+// whitespace, and HTML characters are not escaped.  Equal headers yield
+// byte-identical output.
+//
+// All numeric header fields are encoded as strings, so number formatting
+// does not come into play.
+func (h *Header) CanonicalJSON() ([]byte, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	b, err := marshalHeaderJSON(h, newHeaderJSON(h))
+	if err != nil {
+		return nil, err
+	}
+	// Round-trip through a generic map, which encoding/json marshals with
+	// sorted keys.
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(fields); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
@@ -0,0 +1,73 @@
+package block
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestHeader_CanonicalJSON(t *testing.T) {
+	h := randomHeader(rand.New(rand.NewSource(1)))
+	clone, err := h.CloneViaRLP()
+	if err != nil {
+		t.Fatalf("CloneViaRLP() error = %v", err)
+	}
+	b, err := h.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON() error = %v", err)
+	}
+	cloneB, err := clone.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON() of clone error = %v", err)
+	}
+	if sha256.Sum256(b) != sha256.Sum256(cloneB) {
+		t.Errorf("CanonicalJSON() digests of equal headers differ:\n%s\n%s", b, cloneB)
+	}
+
+	other, err := h.Copy().With().Number(new(big.Int).Add(h.Number(), big.NewInt(1))).Header().CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON() of other header error = %v", err)
+	}
+	if sha256.Sum256(other) == sha256.Sum256(b) {
+		t.Error("CanonicalJSON() digests of different headers are equal")
+	}
+
+	if bytes.ContainsAny(b, " \t\r\n") {
+		t.Errorf("CanonicalJSON() contains whitespace: %s", b)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatalf("cannot parse CanonicalJSON() output: %v", err)
+	}
+	marshaled, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	var wantFields map[string]json.RawMessage
+	if err := json.Unmarshal(marshaled, &wantFields); err != nil {
+		t.Fatalf("cannot parse MarshalJSON() output: %v", err)
+	}
+	if len(fields) != len(wantFields) {
+		t.Errorf("CanonicalJSON() has %d fields, want %d as MarshalJSON", len(fields), len(wantFields))
+	}
+	var keys []string
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.Token() // {
+	for dec.More() {
+		key, _ := dec.Token()
+		keys = append(keys, key.(string))
+		var v json.RawMessage
+		dec.Decode(&v)
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Errorf("CanonicalJSON() keys not sorted: %q", keys)
+	}
+
+	if _, err := (&Header{}).CanonicalJSON(); err != ErrHeaderIsNil {
+		t.Errorf("CanonicalJSON() of empty header error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
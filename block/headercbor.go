@@ -0,0 +1,94 @@
+package block
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+)
+
+// The CBOR form of a header is a map with the same keys as its JSON form.
+// Values are encoded by their Go type in headerJSON: byte arrays and slices as
+// byte strings, big integers as integers or bignums, unsigned integers as
+// unsigned integers, strings as text strings, and nil pointers as null.  The
+// encoding is in the RFC 8949 core deterministic form, so that equal headers
+// produce identical encodings.
+
+var (
+	headerCBOREncMode = func() cbor.EncMode {
+		em, err := cbor.CoreDetEncOptions().EncMode()
+		if err != nil {
+			panic(err)
+		}
+		return em
+	}()
+	headerCBORDecMode = func() cbor.DecMode {
+		dm, err := cbor.DecOptions{}.DecMode()
+		if err != nil {
+			panic(err)
+		}
+		return dm
+	}()
+
+	bigIntType    = reflect.TypeOf(big.Int{})
+	bigIntPtrType = reflect.PtrTo(bigIntType)
+)
+
+// MarshalCBOR encodes the header into its deterministic CBOR form.
+func (h *Header) MarshalCBOR() ([]byte, error) {
+	if h == nil {
+		return nil, ErrHeaderIsNil
+	}
+	enc := reflect.ValueOf(newHeaderJSON(h))
+	fields := make(map[string]interface{}, enc.NumField())
+	for i := 0; i < enc.NumField(); i++ {
+		value := enc.Field(i)
+		// The CBOR library encodes only big.Int itself as a bignum.
+		if value.Type().ConvertibleTo(bigIntPtrType) {
+			value = value.Convert(bigIntPtrType)
+		}
+		fields[headerJSONKey(enc.Type().Field(i))] = value.Interface()
+	}
+	return headerCBOREncMode.Marshal(fields)
+}
+
+// UnmarshalCBOR decodes the CBOR form produced by MarshalCBOR.  As with
+// UnmarshalJSON, the version field selects the concrete header type, and
+// unknown keys are ignored.
+func (h *Header) UnmarshalCBOR(data []byte) error {
+	if h == nil {
+		return ErrHeaderIsNil
+	}
+	var fields map[string]cbor.RawMessage
+	if err := headerCBORDecMode.Unmarshal(data, &fields); err != nil {
+		return errors.Wrap(err, "cannot decode CBOR header")
+	}
+	var dec headerJSON
+	decV := reflect.ValueOf(&dec).Elem()
+	for i := 0; i < decV.NumField(); i++ {
+		key := headerJSONKey(decV.Type().Field(i))
+		raw, ok := fields[key]
+		if !ok {
+			continue
+		}
+		field := decV.Field(i)
+		target := field.Addr()
+		if field.Type().ConvertibleTo(bigIntPtrType) {
+			target = reflect.New(bigIntPtrType)
+		}
+		if err := headerCBORDecMode.Unmarshal(raw, target.Interface()); err != nil {
+			return errors.Wrapf(err, "cannot decode field %#v", key)
+		}
+		if target.Elem().Type() != field.Type() {
+			field.Set(target.Elem().Convert(field.Type()))
+		}
+	}
+	hif, err := dec.header()
+	if err != nil {
+		return err
+	}
+	h.Header = hif
+	h.invalidateHash()
+	return nil
+}
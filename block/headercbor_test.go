@@ -0,0 +1,94 @@
+package block
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fxamacker/cbor/v2"
+
+	blockif "github.com/harmony-one/harmony/block/interface"
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v1 "github.com/harmony-one/harmony/block/v1"
+	v2 "github.com/harmony-one/harmony/block/v2"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_CBORRoundTrip(t *testing.T) {
+	for _, hif := range []blockif.Header{
+		v0.NewHeader(), v1.NewHeader(), v2.NewHeader(), v3.NewHeader(),
+	} {
+		t.Run(headerVersionName(hif), func(t *testing.T) {
+			h := (&Header{Header: hif}).With().
+				ParentHash(common.HexToHash("0x1234")).
+				Number(big.NewInt(1 << 40)).
+				GasLimit(80000000).
+				Time(big.NewInt(1600000000)).
+				UncheckedExtra(bytes.Repeat([]byte{0xab}, 300)).
+				Epoch(big.NewInt(7)).
+				ShardID(3).
+				LastCommitSignature([96]byte{1, 2, 3}).
+				LastCommitBitmap([]byte{0xff}).
+				Vrf([]byte{4, 5}).
+				Header()
+			b, err := h.MarshalCBOR()
+			if err != nil {
+				t.Fatalf("MarshalCBOR() error = %v", err)
+			}
+			b2, err := h.Copy().MarshalCBOR()
+			if err != nil {
+				t.Fatalf("MarshalCBOR() error = %v", err)
+			}
+			if !bytes.Equal(b, b2) {
+				t.Error("MarshalCBOR() is not deterministic")
+			}
+			got := &Header{}
+			if err := got.UnmarshalCBOR(b); err != nil {
+				t.Fatalf("UnmarshalCBOR() error = %v", err)
+			}
+			if got.Version() != h.Version() {
+				t.Errorf("UnmarshalCBOR() got version %#v, want %#v",
+					got.Version(), h.Version())
+			}
+			if got.Hash() != h.Hash() {
+				t.Errorf("UnmarshalCBOR() got hash %x, want %x", got.Hash(), h.Hash())
+			}
+		})
+	}
+}
+
+func TestHeader_UnmarshalCBOR_Truncated(t *testing.T) {
+	b, err := (&Header{Header: v3.NewHeader()}).MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR() error = %v", err)
+	}
+	for _, n := range []int{0, 1, len(b) / 2, len(b) - 1} {
+		if err := (&Header{}).UnmarshalCBOR(b[:n]); err == nil {
+			t.Errorf("UnmarshalCBOR(%d of %d bytes) succeeded", n, len(b))
+		}
+	}
+}
+
+func TestHeader_UnmarshalCBOR_UnknownKey(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(9)).Header()
+	b, err := h.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR() error = %v", err)
+	}
+	var fields map[string]cbor.RawMessage
+	if err := cbor.Unmarshal(b, &fields); err != nil {
+		t.Fatalf("cannot parse MarshalCBOR() output: %v", err)
+	}
+	fields["sideChainID"] = cbor.RawMessage{0x18, 0x2a}
+	if b, err = cbor.Marshal(fields); err != nil {
+		t.Fatalf("cannot re-encode CBOR header: %v", err)
+	}
+	got := &Header{}
+	if err := got.UnmarshalCBOR(b); err != nil {
+		t.Fatalf("UnmarshalCBOR() error = %v", err)
+	}
+	if got.Hash() != h.Hash() {
+		t.Errorf("UnmarshalCBOR() got hash %x, want %x", got.Hash(), h.Hash())
+	}
+}
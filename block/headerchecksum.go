@@ -0,0 +1,52 @@
+package block
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+
+	"github.com/pkg/errors"
+)
+
+// headerChecksumLength is the length of the CRC32 checksum prefix.
+const headerChecksumLength = crc32.Size
+
+var (
+	// ErrChecksumMismatch is returned by DecodeHeaderWithChecksum when the
+	// checksum does not match the encoded header.
+	ErrChecksumMismatch = errors.New("header checksum mismatch")
+	// ErrChecksumTruncated is returned by DecodeHeaderWithChecksum when the
+	// input is too short to hold the checksum.
+	ErrChecksumTruncated = errors.New("header checksum truncated")
+)
+
+// EncodeHeaderWithChecksum returns the tagged RLP encoding of the header, as
+// returned by Bytes, prefixed with its big-endian CRC32 (IEEE) checksum, so
+// that storage corruption can be detected on decoding.
+func EncodeHeaderWithChecksum(h *Header) ([]byte, error) {
+	payload, err := h.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, headerChecksumLength+len(payload))
+	binary.BigEndian.PutUint32(b, crc32.ChecksumIEEE(payload))
+	copy(b[headerChecksumLength:], payload)
+	return b, nil
+}
+
+// DecodeHeaderWithChecksum verifies the checksum of an encoding returned by
+// EncodeHeaderWithChecksum, and decodes the header only if it matches.  The
+// returned error wraps ErrChecksumMismatch or ErrChecksumTruncated if the
+// checksum cannot be verified.
+func DecodeHeaderWithChecksum(b []byte) (*Header, error) {
+	if len(b) < headerChecksumLength {
+		return nil, errors.Wrapf(ErrChecksumTruncated,
+			"%d bytes, need at least %d", len(b), headerChecksumLength)
+	}
+	payload := b[headerChecksumLength:]
+	stored := binary.BigEndian.Uint32(b)
+	if actual := crc32.ChecksumIEEE(payload); actual != stored {
+		return nil, errors.Wrapf(ErrChecksumMismatch,
+			"stored %08x, actual %08x", stored, actual)
+	}
+	return HeaderFromBytes(payload)
+}
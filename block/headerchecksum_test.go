@@ -0,0 +1,58 @@
+package block
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestEncodeHeaderWithChecksum(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).Epoch(big.NewInt(7)).Extra([]byte("extra")).Header()
+	b, err := EncodeHeaderWithChecksum(h)
+	if err != nil {
+		t.Fatalf("EncodeHeaderWithChecksum() error = %v", err)
+	}
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		got, err := DecodeHeaderWithChecksum(b)
+		if err != nil {
+			t.Fatalf("DecodeHeaderWithChecksum() error = %v", err)
+		}
+		if !got.Equal(h) {
+			t.Errorf("DecodeHeaderWithChecksum() = %v, want %v", got, h)
+		}
+	})
+	t.Run("BitFlip", func(t *testing.T) {
+		for _, i := range []int{0, headerChecksumLength, len(b) - 1} {
+			corrupt := append([]byte(nil), b...)
+			corrupt[i] ^= 0x10
+			_, err := DecodeHeaderWithChecksum(corrupt)
+			if errors.Cause(err) != ErrChecksumMismatch {
+				t.Errorf("bit flip at %d: error = %v, want %v", i, err, ErrChecksumMismatch)
+			}
+		}
+	})
+	t.Run("Truncated", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			b       []byte
+			wantErr error
+		}{
+			{"Empty", nil, ErrChecksumTruncated},
+			{"PartialChecksum", b[:headerChecksumLength-1], ErrChecksumTruncated},
+			{"PartialPayload", b[:len(b)-1], ErrChecksumMismatch},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := DecodeHeaderWithChecksum(tt.b)
+				if errors.Cause(err) != tt.wantErr {
+					t.Errorf("DecodeHeaderWithChecksum() error = %v, want %v", err, tt.wantErr)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package block
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// The compact form of a header is a fixed-layout binary for constrained
+// clients such as light wallets.  It carries only the fields below, in this
+// order, with integers in big-endian byte order:
+//
+//	format       1 byte, compactHeaderFormat
+//	number       8 bytes
+//	epoch        8 bytes
+//	shard ID     4 bytes
+//	parent hash  32 bytes
+//	state root   32 bytes
+//	tx root      32 bytes
+//	receipt root 32 bytes
+//	timestamp    8 bytes
+//
+// All other fields, notably the variable-length shard state, cross-links,
+// slashes, VRF/VDF, extra data, commit signature and bitmap, bloom, gas, and
+// coinbase, are left out.  The encoding is thus lossy, and a decoded header
+// does not hash to the same value as the original one.
+
+const (
+	// compactHeaderFormat is the format byte of the compact header form.
+	compactHeaderFormat = 1
+	// CompactHeaderSize is the size of the compact form of a header.
+	CompactHeaderSize = 1 + 8 + 8 + 4 + 4*common.HashLength + 8
+)
+
+// ErrCompactHeaderFormat is returned by DecodeCompactHeader when the input is
+// not a compact header of a known format.
+var ErrCompactHeaderFormat = errors.New("invalid compact header")
+
+// EncodeCompact returns the compact, fixed-layout binary form of the header.
+// See DecodeCompactHeader for the fields that it preserves.  Block numbers,
+// epochs, and timestamps beyond the uint64 range cannot be encoded.
+func (h *Header) EncodeCompact() ([]byte, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	number, err := h.NumberU64()
+	if err != nil {
+		return nil, err
+	}
+	epoch, err := compactUint64("epoch", h.Epoch)
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := compactUint64("time", h.Time)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, CompactHeaderSize)
+	b = append(b, compactHeaderFormat)
+	b = appendUint64(b, number)
+	b = appendUint64(b, epoch)
+	b = appendUint32(b, h.ShardID())
+	for _, hash := range []common.Hash{h.ParentHash(), h.Root(), h.TxHash(), h.ReceiptHash()} {
+		b = append(b, hash[:]...)
+	}
+	return appendUint64(b, timestamp), nil
+}
+
+// DecodeCompactHeader decodes the compact form returned by EncodeCompact into
+// a header of the latest header version.  Only the block number, epoch, shard
+// ID, parent hash, state root, transaction root, receipt root, and timestamp
+// are restored; all other fields are left at their zero values.
+func DecodeCompactHeader(b []byte) (*Header, error) {
+	if len(b) != CompactHeaderSize {
+		return nil, errors.Wrapf(ErrCompactHeaderFormat,
+			"got %d bytes, want %d", len(b), CompactHeaderSize)
+	}
+	if b[0] != compactHeaderFormat {
+		return nil, errors.Wrapf(ErrCompactHeaderFormat, "unknown format %d", b[0])
+	}
+	b = b[1:]
+	next := func(n int) []byte {
+		field := b[:n]
+		b = b[n:]
+		return field
+	}
+	s := (&Header{Header: headerVersions[len(headerVersions)-1].factory()}).With().
+		Number(new(big.Int).SetUint64(binary.BigEndian.Uint64(next(8)))).
+		Epoch(new(big.Int).SetUint64(binary.BigEndian.Uint64(next(8)))).
+		ShardID(binary.BigEndian.Uint32(next(4))).
+		ParentHash(common.BytesToHash(next(common.HashLength))).
+		Root(common.BytesToHash(next(common.HashLength))).
+		TxHash(common.BytesToHash(next(common.HashLength))).
+		ReceiptHash(common.BytesToHash(next(common.HashLength))).
+		Time(new(big.Int).SetUint64(binary.BigEndian.Uint64(next(8))))
+	return s.Header(), nil
+}
+
+// compactUint64 returns the value of the named big integer field returned by
+// get, which must be set and fit in a uint64.
+func compactUint64(name string, get func() *big.Int) (uint64, error) {
+	x := bigOrNil(get)
+	if x == nil || !x.IsUint64() {
+		return 0, errors.Errorf("%s %v cannot be encoded as uint64", name, x)
+	}
+	return x.Uint64(), nil
+}
+
+func appendUint32(b []byte, x uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], x)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], x)
+	return append(b, buf[:]...)
+}
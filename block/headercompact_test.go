@@ -0,0 +1,86 @@
+package block
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_EncodeCompact(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(123456)).
+		Epoch(big.NewInt(789)).
+		ShardID(3).
+		ParentHash(common.HexToHash("0x01")).
+		Root(common.HexToHash("0x02")).
+		TxHash(common.HexToHash("0x03")).
+		ReceiptHash(common.HexToHash("0x04")).
+		Time(big.NewInt(1600000000)).
+		// Excluded from the compact form:
+		GasLimit(80000000).
+		Extra([]byte("extra")).
+		ShardState([]byte{0xc0}).
+		CrossLinks([]byte{0xc0}).
+		Header()
+	b, err := h.EncodeCompact()
+	if err != nil {
+		t.Fatalf("EncodeCompact() error = %v", err)
+	}
+	if len(b) != CompactHeaderSize {
+		t.Errorf("len(EncodeCompact()) = %d, want %d", len(b), CompactHeaderSize)
+	}
+	got, err := DecodeCompactHeader(b)
+	if err != nil {
+		t.Fatalf("DecodeCompactHeader() error = %v", err)
+	}
+	diff := got.DiffFields(h)
+	for _, field := range []string{
+		"Number", "Epoch", "ShardID", "ParentHash", "Root", "TxHash",
+		"ReceiptHash", "Time",
+	} {
+		if d, ok := diff[field]; ok {
+			t.Errorf("%s not preserved: got %s, want %s", field, d[0], d[1])
+		}
+	}
+	for _, field := range []string{"GasLimit", "Extra", "ShardState", "CrossLinks"} {
+		if _, ok := diff[field]; !ok {
+			t.Errorf("%s unexpectedly preserved", field)
+		}
+	}
+}
+
+func TestHeader_EncodeCompact_Errors(t *testing.T) {
+	huge := new(big.Int).Lsh(big.NewInt(1), 64)
+	for name, h := range map[string]*Header{
+		"Nil":          nil,
+		"HugeNumber":   (&Header{Header: v3.NewHeader()}).With().Number(huge).Header(),
+		"HugeEpoch":    (&Header{Header: v3.NewHeader()}).With().Epoch(huge).Header(),
+		"NegativeTime": (&Header{Header: v3.NewHeader()}).With().Time(big.NewInt(-1)).Header(),
+	} {
+		if _, err := h.EncodeCompact(); err == nil {
+			t.Errorf("%s: EncodeCompact() succeeded", name)
+		}
+	}
+}
+
+func TestDecodeCompactHeader_Invalid(t *testing.T) {
+	valid, err := (&Header{Header: v3.NewHeader()}).EncodeCompact()
+	if err != nil {
+		t.Fatalf("EncodeCompact() error = %v", err)
+	}
+	badFormat := append([]byte{99}, valid[1:]...)
+	for name, b := range map[string][]byte{
+		"Empty":     nil,
+		"Truncated": valid[:len(valid)-1],
+		"Trailing":  append(append([]byte{}, valid...), 0),
+		"BadFormat": badFormat,
+	} {
+		if _, err := DecodeCompactHeader(b); errors.Cause(err) != ErrCompactHeaderFormat {
+			t.Errorf("%s: DecodeCompactHeader() error = %v, want %v", name, err, ErrCompactHeaderFormat)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package block
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	blockif "github.com/harmony-one/harmony/block/interface"
+)
+
+// headerField is a named header field accessor.
+type headerField struct {
+	name  string
+	value func(h blockif.Header) interface{}
+}
+
+// headerFields lists the header fields that take part in structural
+// comparison, in comparison order.
+var headerFields = []headerField{
+	{"Number", func(h blockif.Header) interface{} { return h.Number() }},
+	{"ParentHash", func(h blockif.Header) interface{} { return h.ParentHash() }},
+	{"ShardID", func(h blockif.Header) interface{} { return h.ShardID() }},
+	{"Epoch", func(h blockif.Header) interface{} { return h.Epoch() }},
+	{"ViewID", func(h blockif.Header) interface{} { return h.ViewID() }},
+	{"Root", func(h blockif.Header) interface{} { return h.Root() }},
+	{"TxHash", func(h blockif.Header) interface{} { return h.TxHash() }},
+	{"ReceiptHash", func(h blockif.Header) interface{} { return h.ReceiptHash() }},
+	{"OutgoingReceiptHash", func(h blockif.Header) interface{} { return h.OutgoingReceiptHash() }},
+	{"IncomingReceiptHash", func(h blockif.Header) interface{} { return h.IncomingReceiptHash() }},
+	{"Bloom", func(h blockif.Header) interface{} { return h.Bloom() }},
+	{"Coinbase", func(h blockif.Header) interface{} { return h.Coinbase() }},
+	{"Extra", func(h blockif.Header) interface{} { return h.Extra() }},
+	{"GasLimit", func(h blockif.Header) interface{} { return h.GasLimit() }},
+	{"GasUsed", func(h blockif.Header) interface{} { return h.GasUsed() }},
+	{"Time", func(h blockif.Header) interface{} { return h.Time() }},
+	{"MixDigest", func(h blockif.Header) interface{} { return h.MixDigest() }},
+	{"LastCommitSignature", func(h blockif.Header) interface{} { return h.LastCommitSignature() }},
+	{"LastCommitBitmap", func(h blockif.Header) interface{} { return h.LastCommitBitmap() }},
+	{"ShardStateHash", func(h blockif.Header) interface{} { return h.ShardStateHash() }},
+	{"ShardState", func(h blockif.Header) interface{} { return h.ShardState() }},
+	{"Vrf", func(h blockif.Header) interface{} { return h.Vrf() }},
+	{"Vdf", func(h blockif.Header) interface{} { return h.Vdf() }},
+	{"CrossLinks", func(h blockif.Header) interface{} {
+		// Pre-v2 headers log on every CrossLinks() call; avoid the noise.
+		if v, _ := headerVersionOf(h); !v.features.crossLinks {
+			return []byte(nil)
+		}
+		return h.CrossLinks()
+	}},
+	{"Slashes", func(h blockif.Header) interface{} {
+		// Non-v3 headers log on every Slashes() call; avoid the noise.
+		if v, _ := headerVersionOf(h); !v.features.slashes {
+			return []byte(nil)
+		}
+		return h.Slashes()
+	}},
+	{"BaseFee", func(h blockif.Header) interface{} { return h.BaseFee() }},
+}
+
+// headerFieldValuesEqual returns whether two values of the same header field
+// are equal.  Nil and empty byte slices are considered equal.
+func headerFieldValuesEqual(x, y interface{}) bool {
+	switch xv := x.(type) {
+	case *big.Int:
+		yv := y.(*big.Int)
+		if xv == nil || yv == nil {
+			return xv == yv
+		}
+		return xv.Cmp(yv) == 0
+	case []byte:
+		return bytes.Equal(xv, y.([]byte))
+	default:
+		return x == y
+	}
+}
+
+// Equal returns whether the two headers have the same field values.
+//
+// Headers of different versions are compared field by field; fields that a
+// version does not carry read as zero values.  Two nil headers are equal.
+func (h *Header) Equal(other *Header) bool {
+	return firstDifferentField(h, other) == ""
+}
+
+// firstDifferentField returns the name of the first field that differs
+// between the two headers, or an empty string if they are equal.  A nil
+// header differs from a non-nil one in the pseudo-field "Header".
+func firstDifferentField(x, y *Header) string {
+	xNil := x == nil || x.Header == nil
+	yNil := y == nil || y.Header == nil
+	if xNil || yNil {
+		if xNil == yNil {
+			return ""
+		}
+		return "Header"
+	}
+	for _, field := range headerFields {
+		if !headerFieldValuesEqual(field.value(x.Header), field.value(y.Header)) {
+			return field.name
+		}
+	}
+	return ""
+}
+
+// Less reports whether h sorts before other in the total order of headers
+// used for canonical chain selection: by Number, then Epoch, then ViewID if
+// both header versions carry it, and finally by Hash in byte order to break
+// ties.  A nil header sorts before any non-nil one.
+//
+// Less is suitable for sort.Slice.
+func (h *Header) Less(other *Header) bool {
+	hNil, otherNil := isNilHeader(h), isNilHeader(other)
+	if hNil || otherNil {
+		return hNil && !otherNil
+	}
+	if c := compareBigInts(h.Number(), other.Number()); c != 0 {
+		return c < 0
+	}
+	if c := compareBigInts(h.Epoch(), other.Epoch()); c != 0 {
+		return c < 0
+	}
+	hv, _ := headerVersionOf(h.Header)
+	ov, _ := headerVersionOf(other.Header)
+	if hv.features.viewID && ov.features.viewID {
+		if c := compareBigInts(h.ViewID(), other.ViewID()); c != 0 {
+			return c < 0
+		}
+	}
+	hHash, otherHash := h.Hash(), other.Hash()
+	return bytes.Compare(hHash[:], otherHash[:]) < 0
+}
+
+// compareBigInts compares x and y like big.Int.Cmp, with nil sorting first.
+func compareBigInts(x, y *big.Int) int {
+	switch {
+	case x == nil && y == nil:
+		return 0
+	case x == nil:
+		return -1
+	case y == nil:
+		return 1
+	}
+	return x.Cmp(y)
+}
+
+// headerFieldValueString returns the string form of a header field value,
+// with byte arrays and slices in 0x-prefixed hex.
+func headerFieldValueString(x interface{}) string {
+	switch v := x.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case [96]byte:
+		return hexutil.Encode(v[:])
+	case types.Bloom:
+		return hexutil.Encode(v[:])
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// DiffFields returns the fields that differ between the two headers, keyed by
+// field name, with the string forms of the values in h and other.
+//
+// A version difference is reported under the "Version" key, in addition to
+// any field differences.  If exactly one of the headers is nil, the result has
+// only the "Header" key.  Equal headers yield an empty map.
+func (h *Header) DiffFields(other *Header) map[string][2]string {
+	diff := map[string][2]string{}
+	hNil, otherNil := isNilHeader(h), isNilHeader(other)
+	if hNil || otherNil {
+		if hNil != otherNil {
+			names := [2]string{"<header>", "<header>"}
+			if hNil {
+				names[0] = "<nil>"
+			} else {
+				names[1] = "<nil>"
+			}
+			diff["Header"] = names
+		}
+		return diff
+	}
+	if hv, ov := h.Version(), other.Version(); hv != ov {
+		diff["Version"] = [2]string{hv, ov}
+	}
+	for _, field := range headerFields {
+		x, y := field.value(h.Header), field.value(other.Header)
+		if !headerFieldValuesEqual(x, y) {
+			diff[field.name] = [2]string{
+				headerFieldValueString(x), headerFieldValueString(y),
+			}
+		}
+	}
+	return diff
+}
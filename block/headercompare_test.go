@@ -0,0 +1,157 @@
+package block
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v1 "github.com/harmony-one/harmony/block/v1"
+	v2 "github.com/harmony-one/harmony/block/v2"
+	v3 "github.com/harmony-one/harmony/block/v3"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+func TestHeader_Equal(t *testing.T) {
+	base := func() *Header {
+		return (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(5)).
+			ParentHash(common.HexToHash("0xabcd")).
+			Epoch(big.NewInt(1)).
+			ShardID(2).
+			Extra([]byte("extra")).
+			Header()
+	}
+	tests := []struct {
+		name      string
+		x, y      *Header
+		wantField string
+	}{
+		{"Same", base(), base(), ""},
+		{"BothNil", nil, nil, ""},
+		{"NilReceiver", nil, base(), "Header"},
+		{"NilOther", base(), nil, "Header"},
+		{"NilEmbedded", &Header{}, base(), "Header"},
+		{"Number", base(), base().With().Number(big.NewInt(6)).Header(), "Number"},
+		{"ShardID", base(), base().With().ShardID(3).Header(), "ShardID"},
+		{"Extra", base(), base().With().Extra(nil).Header(), "Extra"},
+		{"Root", base(), base().With().Root(common.HexToHash("0x1")).Header(), "Root"},
+		{
+			"MixedVersionSameFields",
+			(&Header{Header: v1.NewHeader()}).With().Number(big.NewInt(5)).Header(),
+			(&Header{Header: v2.NewHeader()}).With().Number(big.NewInt(5)).Header(),
+			"",
+		},
+		{
+			"MixedVersionCrossLinks",
+			(&Header{Header: v1.NewHeader()}).With().Number(big.NewInt(5)).Header(),
+			(&Header{Header: v2.NewHeader()}).With().Number(big.NewInt(5)).
+				CrossLinks([]byte{0xc0}).Header(),
+			"CrossLinks",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := firstDifferentField(tt.x, tt.y); got != tt.wantField {
+				t.Errorf("firstDifferentField() = %#v, want %#v", got, tt.wantField)
+			}
+			if got, want := tt.x.Equal(tt.y), tt.wantField == ""; got != want {
+				t.Errorf("Equal() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestHeader_DiffFields(t *testing.T) {
+	base := func() *Header {
+		return (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(100)).Extra([]byte{1, 2}).Header()
+	}
+	tests := []struct {
+		name  string
+		other *Header
+		want  map[string][2]string
+	}{
+		{"Equal", base(), map[string][2]string{}},
+		{"Extra", base().With().Extra([]byte{3}).Header(),
+			map[string][2]string{"Extra": {"0x0102", "0x03"}}},
+		{"Root", base().With().Root(common.HexToHash("0x01")).Header(),
+			map[string][2]string{"Root": {
+				common.Hash{}.Hex(), common.HexToHash("0x01").Hex(),
+			}}},
+		{"Version", (&Header{Header: v2.NewHeader()}).With().
+			Number(big.NewInt(100)).Extra([]byte{1, 2}).Header(),
+			map[string][2]string{"Version": {"v3", "v2"}}},
+		{"Nil", nil, map[string][2]string{"Header": {"<header>", "<nil>"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base().DiffFields(tt.other); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_Less(t *testing.T) {
+	header := func(number, epoch, viewID int64, extra string) *Header {
+		return (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(number)).
+			Epoch(big.NewInt(epoch)).
+			ViewID(big.NewInt(viewID)).
+			Extra([]byte(extra)).
+			Header()
+	}
+	tiedA, tiedB := header(5, 1, 5, "a"), header(5, 1, 5, "b")
+	if hashA, hashB := tiedA.Hash(), tiedB.Hash(); bytes.Compare(hashA[:], hashB[:]) > 0 {
+		tiedA, tiedB = tiedB, tiedA
+	}
+	// Strictly ascending.
+	ordered := []*Header{
+		nil,
+		header(4, 2, 9, ""),
+		header(5, 0, 9, ""),
+		header(5, 1, 4, ""),
+		tiedA,
+		tiedB,
+		header(5, 1, 6, ""),
+		header(6, 0, 0, ""),
+	}
+	for i, x := range ordered {
+		for j, y := range ordered {
+			if got, want := x.Less(y), i < j; got != want {
+				t.Errorf("ordered[%d].Less(ordered[%d]) = %v, want %v", i, j, got, want)
+			}
+		}
+	}
+	shuffled := make([]*Header, len(ordered))
+	for i, j := range []int{5, 0, 7, 2, 6, 1, 4, 3} {
+		shuffled[i] = ordered[j]
+	}
+	sort.Slice(shuffled, func(i, j int) bool { return shuffled[i].Less(shuffled[j]) })
+	if !reflect.DeepEqual(shuffled, ordered) {
+		t.Error("sort.Slice with Less did not restore the order")
+	}
+}
+
+func TestHeader_Compare_NoErrorLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := utils.Logger()
+	saved := *logger
+	*logger = zerolog.New(&buf)
+	defer func() { *logger = saved }()
+
+	x := (&Header{Header: v0.NewHeader()}).With().Number(big.NewInt(1)).Header()
+	y := x.Copy().With().Number(big.NewInt(2)).Header()
+	x.Equal(y)
+	x.Less(y)
+	x.DiffFields(y)
+	if buf.Len() != 0 {
+		t.Errorf("comparing v0 headers logged %s", buf.String())
+	}
+}
@@ -0,0 +1,77 @@
+package block
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// headerCompressionFlate is the format version byte of compressed header
+// batches: headers framed as by HeadersToBytes, then DEFLATE-compressed.
+const headerCompressionFlate = 1
+
+// MaxDecompressedHeadersSize is the largest size, in bytes, of the framed
+// headers DecompressHeaders accepts once decompressed.  It bounds the memory
+// a small malicious input can make DecompressHeaders allocate, and leaves
+// room for about a hundred thousand headers.
+const MaxDecompressedHeadersSize = 64 << 20
+
+var (
+	// ErrUnknownCompressionFormat is returned by DecompressHeaders when the
+	// input does not start with a known format version byte.
+	ErrUnknownCompressionFormat = errors.New("unknown header compression format")
+
+	// ErrHeadersTooLarge is returned by DecompressHeaders when the input
+	// decompresses to more than MaxDecompressedHeadersSize bytes.
+	ErrHeadersTooLarge = errors.New("decompressed headers too large")
+)
+
+// CompressHeaders encodes the given headers as by HeadersToBytes and
+// compresses the result, which is prefixed with a format version byte, for
+// archival storage.  Compressing the batch as a whole exploits the redundancy
+// between consecutive headers.  Use DecompressHeaders to decode the result.
+func CompressHeaders(headers []*Header) ([]byte, error) {
+	b, err := HeadersToBytes(headers)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(headerCompressionFlate)
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecompressHeaders decodes headers compressed by CompressHeaders.  It fails
+// with ErrHeadersTooLarge without decompressing the rest of the input once
+// more than MaxDecompressedHeadersSize bytes have been decompressed.
+func DecompressHeaders(b []byte) ([]*Header, error) {
+	if len(b) == 0 {
+		return nil, errors.Wrap(ErrUnknownCompressionFormat, "empty input")
+	}
+	if b[0] != headerCompressionFlate {
+		return nil, errors.Wrapf(ErrUnknownCompressionFormat, "format %d", b[0])
+	}
+	r := flate.NewReader(bytes.NewReader(b[1:]))
+	defer r.Close()
+	frames, err := ioutil.ReadAll(io.LimitReader(r, MaxDecompressedHeadersSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot decompress headers")
+	}
+	if len(frames) > MaxDecompressedHeadersSize {
+		return nil, errors.Wrapf(ErrHeadersTooLarge,
+			"more than %d bytes", MaxDecompressedHeadersSize)
+	}
+	return HeadersFromBytes(frames)
+}
@@ -0,0 +1,113 @@
+package block
+
+import (
+	"bytes"
+	"compress/flate"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestCompressHeaders(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	// A chain segment: consecutive headers share most of their field values.
+	headers := make([]*Header, 100)
+	parent := common.Hash{}
+	for i := range headers {
+		var root common.Hash
+		rnd.Read(root[:])
+		headers[i] = (&Header{Header: v3.NewHeader()}).With().
+			ParentHash(parent).
+			Number(big.NewInt(int64(1000 + i))).
+			Epoch(big.NewInt(10)).
+			ViewID(big.NewInt(int64(1000 + i))).
+			ShardID(1).
+			Time(big.NewInt(int64(1600000000 + 2*i))).
+			GasLimit(80000000).
+			Root(root).
+			Extra([]byte("harmony")).
+			Header()
+		parent = headers[i].Hash()
+	}
+	b, err := CompressHeaders(headers)
+	if err != nil {
+		t.Fatalf("CompressHeaders() error = %v", err)
+	}
+	raw, err := HeadersToBytes(headers)
+	if err != nil {
+		t.Fatalf("HeadersToBytes() error = %v", err)
+	}
+	t.Logf("compressed %d headers from %d to %d bytes (ratio %.2f)",
+		len(headers), len(raw), len(b), float64(len(raw))/float64(len(b)))
+	if len(b) >= len(raw) {
+		t.Errorf("compressed size %d is not below raw size %d", len(b), len(raw))
+	}
+	got, err := DecompressHeaders(b)
+	if err != nil {
+		t.Fatalf("DecompressHeaders() error = %v", err)
+	}
+	if len(got) != len(headers) {
+		t.Fatalf("decompressed %d headers, want %d", len(got), len(headers))
+	}
+	for i := range headers {
+		if got[i].Hash() != headers[i].Hash() {
+			t.Errorf("header #%d: hash %x, want %x", i, got[i].Hash(), headers[i].Hash())
+		}
+	}
+}
+
+func TestCompressHeaders_Empty(t *testing.T) {
+	b, err := CompressHeaders(nil)
+	if err != nil {
+		t.Fatalf("CompressHeaders() error = %v", err)
+	}
+	got, err := DecompressHeaders(b)
+	if err != nil || len(got) != 0 {
+		t.Errorf("DecompressHeaders() = %v, %v; want no headers", got, err)
+	}
+}
+
+func TestDecompressHeaders_Invalid(t *testing.T) {
+	for _, b := range [][]byte{nil, {0}, {99, 1, 2}} {
+		if _, err := DecompressHeaders(b); errors.Cause(err) != ErrUnknownCompressionFormat {
+			t.Errorf("DecompressHeaders(%x) error = %v, want %v", b, err, ErrUnknownCompressionFormat)
+		}
+	}
+	if _, err := DecompressHeaders([]byte{headerCompressionFlate, 0xff}); err == nil {
+		t.Error("DecompressHeaders() of corrupt data succeeded")
+	}
+}
+
+func TestDecompressHeaders_TooLarge(t *testing.T) {
+	compress := func(size int) []byte {
+		var buf bytes.Buffer
+		buf.WriteByte(headerCompressionFlate)
+		w, err := flate.NewWriter(&buf, flate.BestSpeed)
+		if err != nil {
+			t.Fatalf("flate.NewWriter() error = %v", err)
+		}
+		if _, err := w.Write(make([]byte, size)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		return buf.Bytes()
+	}
+	b := compress(MaxDecompressedHeadersSize + 1)
+	if _, err := DecompressHeaders(b); errors.Cause(err) != ErrHeadersTooLarge {
+		t.Errorf("DecompressHeaders() of %d compressed bytes error = %v, want %v",
+			len(b), err, ErrHeadersTooLarge)
+	}
+	// Exactly at the limit the input is decompressed and handed to
+	// HeadersFromBytes, which rejects the empty frames.
+	b = compress(MaxDecompressedHeadersSize)
+	if _, err := DecompressHeaders(b); errors.Cause(err) != ErrCorruptHeaderFrames {
+		t.Errorf("DecompressHeaders() at the limit error = %v, want %v", err, ErrCorruptHeaderFrames)
+	}
+}
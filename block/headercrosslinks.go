@@ -0,0 +1,59 @@
+package block
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+)
+
+// ErrCrossLinksNotSupported is returned by DecodedCrossLinks for header
+// versions that do not carry cross-links.
+var ErrCrossLinksNotSupported = errors.New("header version does not carry cross-links")
+
+// CrossLink is a cross-link as encoded in beacon chain headers: a reference to
+// a canonical non-beacon block header, with the commit signature over it.
+//
+// It has the same RLP layout as core/types.CrossLink, which cannot be used here
+// because core/types depends on this package.
+type CrossLink struct {
+	Hash        common.Hash
+	BlockNumber *big.Int
+	ViewID      *big.Int
+	Signature   [96]byte
+	Bitmap      []byte
+	ShardID     uint32
+	Epoch       *big.Int
+}
+
+// CrossLinksChecked returns the RLP-encoded cross-links of the header, and
+// whether the header version carries cross-links.  Versions without
+// cross-links return (nil, false), whereas CrossLinks logs an error for them.
+func (h *Header) CrossLinksChecked() ([]byte, bool) {
+	if v, _ := headerVersionOf(h.Header); !v.features.crossLinks {
+		return nil, false
+	}
+	return h.Header.CrossLinks(), true
+}
+
+// DecodedCrossLinks returns the decoded cross-links of the header, which are
+// empty unless it is a beacon chain header including cross-links.  It returns
+// ErrCrossLinksNotSupported for header versions without cross-links.
+func (h *Header) DecodedCrossLinks() ([]CrossLink, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	data, ok := h.CrossLinksChecked()
+	if !ok {
+		return nil, errors.Wrapf(ErrCrossLinksNotSupported, "version %s", h.Version())
+	}
+	if len(data) == 0 {
+		return []CrossLink{}, nil
+	}
+	var crossLinks []CrossLink
+	if err := rlp.DecodeBytes(data, &crossLinks); err != nil {
+		return nil, errors.Wrap(err, "cannot decode cross-links")
+	}
+	return crossLinks, nil
+}
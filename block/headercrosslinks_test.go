@@ -0,0 +1,73 @@
+package block
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v1 "github.com/harmony-one/harmony/block/v1"
+	v2 "github.com/harmony-one/harmony/block/v2"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_DecodedCrossLinks(t *testing.T) {
+	want := []CrossLink{{
+		Hash:        common.HexToHash("0x1234"),
+		BlockNumber: big.NewInt(100),
+		ViewID:      big.NewInt(101),
+		Signature:   [96]byte{1, 2, 3},
+		Bitmap:      []byte{0xff},
+		ShardID:     1,
+		Epoch:       big.NewInt(7),
+	}, {
+		Hash:        common.HexToHash("0x5678"),
+		BlockNumber: big.NewInt(200),
+		ViewID:      big.NewInt(201),
+		Bitmap:      []byte{0x0f},
+		ShardID:     2,
+		Epoch:       big.NewInt(7),
+	}}
+	data, err := rlp.EncodeToBytes(want)
+	if err != nil {
+		t.Fatalf("cannot encode cross-links: %v", err)
+	}
+	tests := []struct {
+		name    string
+		h       *Header
+		want    []CrossLink
+		wantErr error
+	}{
+		{"v2", (&Header{Header: v2.NewHeader()}).With().CrossLinks(data).Header(), want, nil},
+		{"v3", (&Header{Header: v3.NewHeader()}).With().CrossLinks(data).Header(), want, nil},
+		{"Empty", &Header{Header: v3.NewHeader()}, []CrossLink{}, nil},
+		{"Legacy", &Header{Header: v0.NewHeader()}, nil, ErrCrossLinksNotSupported},
+		{"v1", &Header{Header: v1.NewHeader()}, nil, ErrCrossLinksNotSupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.h.DecodedCrossLinks()
+			if errors.Cause(err) != tt.wantErr {
+				t.Fatalf("DecodedCrossLinks() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodedCrossLinks() = %+v, want %+v", got, tt.want)
+			}
+			raw, ok := tt.h.CrossLinksChecked()
+			if ok != (tt.wantErr == nil) {
+				t.Errorf("CrossLinksChecked() supported = %v, want %v", ok, tt.wantErr == nil)
+			}
+			if !ok && raw != nil {
+				t.Errorf("CrossLinksChecked() = %x, want nil", raw)
+			}
+		})
+	}
+	corrupt := (&Header{Header: v3.NewHeader()}).With().CrossLinks([]byte{0xc5, 0x80}).Header()
+	if _, err := corrupt.DecodedCrossLinks(); err == nil {
+		t.Error("DecodedCrossLinks() of corrupt cross-links succeeded")
+	}
+}
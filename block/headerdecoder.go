@@ -0,0 +1,60 @@
+package block
+
+import (
+	"io"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+)
+
+// HeaderDecoder decodes a stream of back-to-back tagged RLP header encodings,
+// one header at a time.
+type HeaderDecoder struct {
+	s   *rlp.Stream
+	n   int
+	err error
+}
+
+// NewHeaderDecoder returns a decoder reading headers from r.
+func NewHeaderDecoder(r io.Reader) *HeaderDecoder {
+	return &HeaderDecoder{s: rlp.NewStream(r, 0)}
+}
+
+// Next decodes the next header from the stream.  It returns false with a nil
+// error once the stream ends cleanly between two headers.  A truncated or
+// corrupt header is an error, after which Next keeps returning the same error.
+func (d *HeaderDecoder) Next() (*Header, bool, error) {
+	if d.err != nil {
+		return nil, false, d.err
+	}
+	if _, _, err := d.s.Kind(); err == io.EOF {
+		return nil, false, nil
+	} else if err != nil {
+		d.err = errors.Wrapf(err, "cannot decode header #%d", d.n)
+		return nil, false, d.err
+	}
+	h := &Header{}
+	if err := h.DecodeRLP(d.s); err != nil {
+		d.err = errors.Wrapf(err, "cannot decode header #%d", d.n)
+		return nil, false, d.err
+	}
+	d.n++
+	return h, true, nil
+}
+
+// DecodeHeaders decodes all headers from the given back-to-back tagged RLP
+// header encodings, until the end of r.
+func DecodeHeaders(r io.Reader) ([]*Header, error) {
+	var headers []*Header
+	d := NewHeaderDecoder(r)
+	for {
+		h, ok, err := d.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return headers, nil
+		}
+		headers = append(headers, h)
+	}
+}
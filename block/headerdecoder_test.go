@@ -0,0 +1,90 @@
+package block
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v2 "github.com/harmony-one/harmony/block/v2"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func encodeHeaderStream(t *testing.T, headers ...*Header) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, h := range headers {
+		if err := h.EncodeRLP(&buf); err != nil {
+			t.Fatalf("EncodeRLP() error = %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func mixedVersionHeaders() []*Header {
+	return []*Header{
+		(&Header{Header: v0.NewHeader()}).With().Number(big.NewInt(1)).Header(),
+		(&Header{Header: v2.NewHeader()}).With().Number(big.NewInt(2)).Header(),
+		(&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(3)).Header(),
+	}
+}
+
+func TestHeaderDecoder_Next(t *testing.T) {
+	want := mixedVersionHeaders()
+	d := NewHeaderDecoder(bytes.NewReader(encodeHeaderStream(t, want...)))
+	for i, w := range want {
+		got, ok, err := d.Next()
+		if err != nil || !ok {
+			t.Fatalf("Next() #%d = _, %v, %v", i, ok, err)
+		}
+		if reflect.TypeOf(got.Header) != reflect.TypeOf(w.Header) {
+			t.Errorf("Next() #%d got type %T, want %T", i, got.Header, w.Header)
+		}
+		if !got.Equal(w) {
+			t.Errorf("Next() #%d got %v, want %v", i, got, w)
+		}
+	}
+	if got, ok, err := d.Next(); got != nil || ok || err != nil {
+		t.Errorf("Next() at end = %v, %v, %v; want nil, false, nil", got, ok, err)
+	}
+}
+
+func TestHeaderDecoder_TruncatedTail(t *testing.T) {
+	b := encodeHeaderStream(t, mixedVersionHeaders()...)
+	d := NewHeaderDecoder(bytes.NewReader(b[:len(b)-3]))
+	for i := 0; i < 2; i++ {
+		if _, ok, err := d.Next(); err != nil || !ok {
+			t.Fatalf("Next() #%d = _, %v, %v", i, ok, err)
+		}
+	}
+	if _, ok, err := d.Next(); err == nil || ok {
+		t.Errorf("Next() on truncated header = _, %v, %v; want error", ok, err)
+	}
+	if _, _, err := d.Next(); err == nil {
+		t.Error("Next() after error expected the error again")
+	}
+}
+
+func TestDecodeHeaders(t *testing.T) {
+	want := mixedVersionHeaders()
+	b := encodeHeaderStream(t, want...)
+	got, err := DecodeHeaders(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("DecodeHeaders() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DecodeHeaders() got %d headers, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("DecodeHeaders() header #%d got %v, want %v", i, got[i], want[i])
+		}
+	}
+	if _, err := DecodeHeaders(bytes.NewReader(append(b, 0xc5, 0x80))); err == nil {
+		t.Error("DecodeHeaders() expected error for corrupt tail")
+	}
+	if got, err := DecodeHeaders(bytes.NewReader(nil)); err != nil || len(got) != 0 {
+		t.Errorf("DecodeHeaders() on empty input = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,47 @@
+package block
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Describe returns a multi-line, human-readable summary of the header for
+// debugging, with one aligned "key: value" pair per line.  Hashes are
+// truncated to their first and last four bytes; use String for full detail.
+func (h *Header) Describe() string {
+	if isNilHeader(h) {
+		return "<nil header>"
+	}
+	gasUsage := "n/a"
+	if h.GasLimit() > 0 {
+		gasUsage = fmt.Sprintf("%.2f%%", h.GasUtilization()*100)
+	}
+	version := h.Version()
+	if version == "" {
+		version = fmt.Sprintf("unregistered (%T)", h.Header)
+	}
+	lines := []struct{ key, value string }{
+		{"Version", version},
+		{"Hash", truncateHash(h.Hash())},
+		{"ParentHash", truncateHash(h.ParentHash())},
+		{"Shard", fmt.Sprint(h.ShardID())},
+		{"Epoch", h.Epoch().String()},
+		{"Number", h.Number().String()},
+		{"ViewID", h.ViewID().String()},
+		{"Gas", fmt.Sprintf("%d / %d (%s)", h.GasUsed(), h.GasLimit(), gasUsage)},
+		{"LastBlockInEpoch", fmt.Sprint(h.IsLastBlockInEpoch())},
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		fmt.Fprintf(&b, "%-17s %s\n", line.key+":", line.value)
+	}
+	return b.String()
+}
+
+// truncateHash returns the hex form of the first and last four bytes of the
+// given hash, e.g. "0x12345678..9abcdef0".
+func truncateHash(hash common.Hash) string {
+	return fmt.Sprintf("0x%x..%x", hash[:4], hash[common.HashLength-4:])
+}
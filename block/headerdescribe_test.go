@@ -0,0 +1,43 @@
+package block
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_Describe(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		ParentHash(common.HexToHash("0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20")).
+		ShardID(2).
+		Epoch(big.NewInt(7)).
+		Number(big.NewInt(100)).
+		GasLimit(1000).
+		GasUsed(250).
+		ShardState([]byte{0xc0}).
+		Header()
+	got := h.Describe()
+	for _, want := range []string{
+		"Version:          v3\n",
+		"ParentHash:       0x01020304..1d1e1f20\n",
+		"Shard:            2\n",
+		"Epoch:            7\n",
+		"Number:           100\n",
+		"Gas:              250 / 1000 (25.00%)\n",
+		"LastBlockInEpoch: true\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Describe() missing line %q in:\n%s", want, got)
+		}
+	}
+	if full := h.Hash().Hex(); strings.Contains(got, full) {
+		t.Errorf("Describe() contains untruncated hash %s", full)
+	}
+	if !strings.Contains(got, truncateHash(h.Hash())) {
+		t.Errorf("Describe() missing truncated hash in:\n%s", got)
+	}
+}
@@ -0,0 +1,64 @@
+package block
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/taggedrlp"
+)
+
+// HeaderDecodeError is the error returned when a header encoding cannot be
+// decoded.  It wraps the underlying cause, which errors.Cause, errors.Is, and
+// errors.As see through; e.g. an unknown version tag still matches
+// ErrUnknownHeaderVersion.
+type HeaderDecodeError struct {
+	// Version is the name of the header version of the encoding, as far as
+	// it could be determined from the version tag envelope.
+	Version string
+	// Err is the underlying cause.
+	Err error
+}
+
+func (e *HeaderDecodeError) Error() string {
+	return "cannot decode " + e.Version + " header: " + e.Err.Error()
+}
+
+// Cause returns the underlying cause, for errors.Cause.
+func (e *HeaderDecodeError) Cause() error { return e.Err }
+
+// Unwrap returns the underlying cause, for errors.Is and errors.As.
+func (e *HeaderDecodeError) Unwrap() error { return e.Err }
+
+// HeaderValidationError is the error returned when a header fails a
+// validation check.  Field names the offending header field, e.g.
+// "ParentHash" or "GasUsed".  It wraps the underlying cause, which is usually
+// one of the Err* variables of this package; errors.Cause, errors.Is, and
+// errors.As see through it.
+type HeaderValidationError struct {
+	Field string
+	Err   error
+}
+
+func (e *HeaderValidationError) Error() string {
+	return "invalid header " + e.Field + ": " + e.Err.Error()
+}
+
+// Cause returns the underlying cause, for errors.Cause.
+func (e *HeaderValidationError) Cause() error { return e.Err }
+
+// Unwrap returns the underlying cause, for errors.Is and errors.As.
+func (e *HeaderValidationError) Unwrap() error { return e.Err }
+
+// invalidField returns a validation error for the given field.
+func invalidField(field string, err error) error {
+	return &HeaderValidationError{Field: field, Err: err}
+}
+
+// encodedHeaderVersion returns the name of the header version of the given
+// tagged RLP header encoding, judging by its version tag envelope only.
+func encodedHeaderVersion(raw []byte) string {
+	var e taggedrlp.Envelope
+	if rlp.DecodeBytes(raw, &e) == nil &&
+		e.Sig == taggedrlp.EnvelopeSignature && e.Tag != taggedrlp.LegacyTag {
+		return string(e.Tag)
+	}
+	return legacyVersionName
+}
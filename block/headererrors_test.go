@@ -0,0 +1,103 @@
+package block
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeaderValidationError(t *testing.T) {
+	parent := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).
+		Time(big.NewInt(1000)).
+		GasLimit(10).
+		Header()
+	child := func() HeaderFieldSetter {
+		return (&Header{Header: v3.NewHeader()}).With().
+			ParentHash(parent.Hash()).
+			Number(big.NewInt(101)).
+			Time(big.NewInt(1001)).
+			GasLimit(10)
+	}
+	tests := []struct {
+		name      string
+		err       error
+		wantField string
+		wantCause error
+	}{
+		{
+			"ParentHash",
+			child().ParentHash(common.Hash{1}).Header().ValidateAgainstParent(parent),
+			"ParentHash", ErrParentHashMismatch,
+		},
+		{
+			"Time",
+			child().Time(big.NewInt(999)).Header().ValidateAgainstParent(parent),
+			"Time", ErrTimeNotIncreasing,
+		},
+		{
+			"GasUsed",
+			child().GasUsed(11).Header().ValidateGas(),
+			"GasUsed", ErrGasUsedExceedsLimit,
+		},
+		{
+			"Extra",
+			child().Extra(make([]byte, 2)).Header().ValidateExtraSize(1, false),
+			"Extra", ErrExtraTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fieldErr *HeaderValidationError
+			if !errors.As(tt.err, &fieldErr) {
+				t.Fatalf("error = %v (type %T), want *HeaderValidationError", tt.err, tt.err)
+			}
+			if fieldErr.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", fieldErr.Field, tt.wantField)
+			}
+			if errors.Cause(tt.err) != tt.wantCause || !errors.Is(tt.err, tt.wantCause) {
+				t.Errorf("error = %v, want cause %v", tt.err, tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestHeaderDecodeError(t *testing.T) {
+	b, err := (&Header{Header: v3.NewHeader()}).Bytes()
+	if err != nil {
+		t.Fatalf("cannot encode header: %v", err)
+	}
+	// Truncate the v3 payload inside an otherwise well-formed envelope.
+	var envelope []interface{}
+	if err := rlp.DecodeBytes(b, &envelope); err != nil {
+		t.Fatalf("cannot decode envelope: %v", err)
+	}
+	envelope[len(envelope)-1] = []byte{0xc1, 0xff}
+	corrupt, err := rlp.EncodeToBytes(envelope)
+	if err != nil {
+		t.Fatalf("cannot encode corrupt header: %v", err)
+	}
+
+	err = rlp.DecodeBytes(corrupt, &Header{})
+	var decodeErr *HeaderDecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("DecodeRLP() error = %v (type %T), want *HeaderDecodeError", err, err)
+	}
+	if decodeErr.Version != "v3" {
+		t.Errorf("Version = %q, want %q", decodeErr.Version, "v3")
+	}
+	if errors.Cause(err) != decodeErr.Err {
+		t.Errorf("errors.Cause() = %v, want %v", errors.Cause(err), decodeErr.Err)
+	}
+
+	// The end of an enclosing list is not a decode error.
+	var headers []*Header
+	if err := rlp.DecodeBytes([]byte{0xc0}, &headers); err != nil || len(headers) != 0 {
+		t.Errorf("decoding empty list = %v, %v; want empty, nil", headers, err)
+	}
+}
@@ -0,0 +1,60 @@
+package block
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// headerEthJSON is the Ethereum-compatible JSON representation of a header,
+// with the header fields of an eth_getBlockByHash result.
+type headerEthJSON struct {
+	Number          *hexutil.Big     `json:"number"`
+	Hash            common.Hash      `json:"hash"`
+	ParentHash      common.Hash      `json:"parentHash"`
+	Nonce           types.BlockNonce `json:"nonce"`
+	MixDigest       common.Hash      `json:"mixHash"`
+	UncleHash       common.Hash      `json:"sha3Uncles"`
+	Bloom           types.Bloom      `json:"logsBloom"`
+	Root            common.Hash      `json:"stateRoot"`
+	Coinbase        common.Address   `json:"miner"`
+	Difficulty      *hexutil.Big     `json:"difficulty"`
+	TotalDifficulty *hexutil.Big     `json:"totalDifficulty"`
+	Extra           hexutil.Bytes    `json:"extraData"`
+	GasLimit        hexutil.Uint64   `json:"gasLimit"`
+	GasUsed         hexutil.Uint64   `json:"gasUsed"`
+	Time            *hexutil.Big     `json:"timestamp"`
+	TxHash          common.Hash      `json:"transactionsRoot"`
+	ReceiptHash     common.Hash      `json:"receiptsRoot"`
+}
+
+// MarshalJSONEthCompat returns the JSON form of the header with the key set
+// of Ethereum, so that Ethereum tooling such as block explorers can read it.
+// Fields that Harmony does not use are filled with their canonical Ethereum
+// values for a block without proof of work or uncles: the empty uncle hash,
+// a zero nonce, and zero (total) difficulty.
+func (h *Header) MarshalJSONEthCompat() ([]byte, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	return json.Marshal(headerEthJSON{
+		Number:          (*hexutil.Big)(h.Number()),
+		Hash:            h.Hash(),
+		ParentHash:      h.ParentHash(),
+		MixDigest:       h.MixDigest(),
+		UncleHash:       types.EmptyUncleHash,
+		Bloom:           h.Bloom(),
+		Root:            h.Root(),
+		Coinbase:        h.Coinbase(),
+		Difficulty:      new(hexutil.Big),
+		TotalDifficulty: new(hexutil.Big),
+		Extra:           h.Extra(),
+		GasLimit:        hexutil.Uint64(h.GasLimit()),
+		GasUsed:         hexutil.Uint64(h.GasUsed()),
+		Time:            (*hexutil.Big)(h.Time()),
+		TxHash:          h.TxHash(),
+		ReceiptHash:     h.ReceiptHash(),
+	})
+}
@@ -0,0 +1,54 @@
+package block
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_MarshalJSONEthCompat(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).GasLimit(80000000).Header()
+	b, err := h.MarshalJSONEthCompat()
+	if err != nil {
+		t.Fatalf("MarshalJSONEthCompat() error = %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("cannot parse MarshalJSONEthCompat() output: %v", err)
+	}
+	wantKeys := []string{
+		"number", "hash", "parentHash", "nonce", "mixHash", "sha3Uncles",
+		"logsBloom", "stateRoot", "miner", "difficulty", "totalDifficulty",
+		"extraData", "gasLimit", "gasUsed", "timestamp", "transactionsRoot",
+		"receiptsRoot",
+	}
+	var gotKeys []string
+	for key := range got {
+		gotKeys = append(gotKeys, key)
+	}
+	sort.Strings(gotKeys)
+	sort.Strings(wantKeys)
+	if strings.Join(gotKeys, ",") != strings.Join(wantKeys, ",") {
+		t.Errorf("MarshalJSONEthCompat() keys = %v, want %v", gotKeys, wantKeys)
+	}
+	for key, want := range map[string]string{
+		"sha3Uncles":      types.EmptyUncleHash.Hex(),
+		"nonce":           "0x0000000000000000",
+		"difficulty":      "0x0",
+		"totalDifficulty": "0x0",
+		"number":          "0x64",
+		"gasLimit":        "0x4c4b400",
+		"hash":            h.Hash().Hex(),
+	} {
+		if got[key] != want {
+			t.Errorf("MarshalJSONEthCompat() %s = %s, want %s", key, got[key], want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package block
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/harmony-one/harmony/internal/params"
+)
+
+// Extra data layout boundaries, following the Clique convention: the extra
+// data starts with a fixed-length vanity prefix and ends with a fixed-length
+// signature (seal), with optional data in between.
+const (
+	// ExtraVanityLength is the length of the vanity prefix of extra data.
+	ExtraVanityLength = 32
+	// ExtraSealLength is the length of the seal suffix of extra data, i.e.
+	// a 65-byte secp256k1 signature.
+	ExtraSealLength = 65
+
+	// MaxExtraDataSize is the protocol limit on the extra data length of
+	// non-genesis headers.
+	MaxExtraDataSize = int(params.MaximumExtraDataSize)
+)
+
+// ErrExtraTooLarge is returned by ValidateExtraSize when the extra data
+// exceeds the size limit.
+var ErrExtraTooLarge = errors.New("extra data too large")
+
+// ExtraVanity returns the vanity prefix of the extra data, or an empty slice
+// if the extra data is shorter than ExtraVanityLength.
+//
+// The returned slice is a copy; the caller may do anything with it.
+func (h *Header) ExtraVanity() []byte {
+	extra := h.Extra()
+	if len(extra) < ExtraVanityLength {
+		return []byte{}
+	}
+	return extra[:ExtraVanityLength:ExtraVanityLength]
+}
+
+// ExtraSeal returns the seal suffix of the extra data, or an empty slice if
+// the extra data is too short to hold both the vanity prefix and the seal.
+//
+// The returned slice is a copy; the caller may do anything with it.
+func (h *Header) ExtraSeal() []byte {
+	extra := h.Extra()
+	if len(extra) < ExtraVanityLength+ExtraSealLength {
+		return []byte{}
+	}
+	return extra[len(extra)-ExtraSealLength:]
+}
+
+// ValidateExtraSize checks that the extra data is at most maxBytes long; pass
+// MaxExtraDataSize for the protocol limit.  If exemptGenesis is true, the
+// genesis header passes regardless of its extra data length, as genesis extra
+// data is not subject to the protocol limit.
+//
+// The returned error wraps ErrExtraTooLarge.
+func (h *Header) ValidateExtraSize(maxBytes int, exemptGenesis bool) error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	if exemptGenesis && h.IsGenesis() {
+		return nil
+	}
+	if size := len(h.Extra()); size > maxBytes {
+		return invalidField("Extra", errors.Wrapf(ErrExtraTooLarge, "%d bytes, limit %d", size, maxBytes))
+	}
+	return nil
+}
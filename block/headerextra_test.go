@@ -0,0 +1,79 @@
+package block
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_ExtraVanitySeal(t *testing.T) {
+	extra := make([]byte, ExtraVanityLength+10+ExtraSealLength)
+	for i := range extra {
+		extra[i] = byte(i)
+	}
+	tests := []struct {
+		name       string
+		extra      []byte
+		wantVanity []byte
+		wantSeal   []byte
+	}{
+		{"Empty", nil, []byte{}, []byte{}},
+		{"ShorterThanVanity", extra[:ExtraVanityLength-1], []byte{}, []byte{}},
+		{"VanityOnly", extra[:ExtraVanityLength],
+			extra[:ExtraVanityLength], []byte{}},
+		{"ShorterThanSeal", extra[:ExtraVanityLength+ExtraSealLength-1],
+			extra[:ExtraVanityLength], []byte{}},
+		{"VanityAndSeal", extra[:ExtraVanityLength+ExtraSealLength],
+			extra[:ExtraVanityLength], extra[ExtraVanityLength : ExtraVanityLength+ExtraSealLength]},
+		{"Longer", extra,
+			extra[:ExtraVanityLength], extra[len(extra)-ExtraSealLength:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := (&Header{Header: v3.NewHeader()}).With().UncheckedExtra(tt.extra).Header()
+			if got := h.ExtraVanity(); got == nil || !bytes.Equal(got, tt.wantVanity) {
+				t.Errorf("ExtraVanity() = %x, want %x", got, tt.wantVanity)
+			}
+			if got := h.ExtraSeal(); got == nil || !bytes.Equal(got, tt.wantSeal) {
+				t.Errorf("ExtraSeal() = %x, want %x", got, tt.wantSeal)
+			}
+		})
+	}
+}
+
+func TestHeader_ValidateExtraSize(t *testing.T) {
+	header := func(number int64, extraSize int) *Header {
+		b := (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(number)).
+			UncheckedExtra(make([]byte, extraSize))
+		if number > 0 {
+			b = b.ParentHash(common.HexToHash("0x1234"))
+		}
+		return b.Header()
+	}
+	tests := []struct {
+		name          string
+		h             *Header
+		exemptGenesis bool
+		wantErr       error
+	}{
+		{"AtLimit", header(1, MaxExtraDataSize), false, nil},
+		{"OverLimit", header(1, MaxExtraDataSize+1), false, ErrExtraTooLarge},
+		{"OverLimitNotGenesisExempt", header(1, MaxExtraDataSize+1), true, ErrExtraTooLarge},
+		{"GenesisExempt", header(0, MaxExtraDataSize+1), true, nil},
+		{"GenesisNotExempt", header(0, MaxExtraDataSize+1), false, ErrExtraTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.h.ValidateExtraSize(MaxExtraDataSize, tt.exemptGenesis)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("ValidateExtraSize() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
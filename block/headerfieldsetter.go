@@ -5,6 +5,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/harmony-one/harmony/shard"
 )
 
 // HeaderFieldSetter is a header field setter.
@@ -12,6 +15,34 @@ import (
 // See NewHeaderWith for how it is used.
 type HeaderFieldSetter struct {
 	h *Header
+
+	// parent, if set by ParentContext, constrains the header being built.
+	parent *Header
+	// numberSet tells whether Number has been called.
+	numberSet bool
+	// extraErr, if not nil, is the error from the last Extra call, reported
+	// by the terminal HeaderChecked call.
+	extraErr error
+	// shardStateErr, if not nil, is the error from the last ShardStateStruct
+	// call, reported by the terminal Header or HeaderChecked call.
+	shardStateErr error
+	// randomnessErr, if not nil, is the error from the first VRF or VDF call
+	// on a header version without randomness, reported by the terminal
+	// Header or HeaderChecked call.
+	randomnessErr error
+}
+
+// ErrRandomnessNotSupported is reported by HeaderChecked after VRF or VDF on
+// a header version that does not carry VRF and VDF outputs.
+var ErrRandomnessNotSupported = errors.New("header version does not carry VRF/VDF")
+
+// ParentContext makes the header being built a child of the given parent
+// header: unless Number is explicitly set, the block number defaults to the
+// parent number plus one, and Header panics if the epoch is lower than that of
+// the parent.  Without a parent context, the setters are unconstrained.
+func (s HeaderFieldSetter) ParentContext(parent *Header) HeaderFieldSetter {
+	s.parent = parent
+	return s
 }
 
 // ParentHash sets the parent hash field.
@@ -67,6 +98,7 @@ func (s HeaderFieldSetter) Bloom(newBloom types.Bloom) HeaderFieldSetter {
 // It stores a copy; the caller may freely modify the original.
 func (s HeaderFieldSetter) Number(newNumber *big.Int) HeaderFieldSetter {
 	s.h.SetNumber(newNumber)
+	s.numberSet = true
 	return s
 }
 
@@ -90,11 +122,30 @@ func (s HeaderFieldSetter) Time(newTime *big.Int) HeaderFieldSetter {
 	return s
 }
 
-// Extra sets the extra data field of this block.
+// Extra sets the extra data field of this block.  Extra data longer than
+// MaxExtraDataSize is an error reported by the terminal HeaderChecked call;
+// Header does not apply the limit.  Use UncheckedExtra to construct genesis
+// headers with HeaderChecked.
 //
 // It stores a copy; the caller may freely modify the original.
 func (s HeaderFieldSetter) Extra(newExtra []byte) HeaderFieldSetter {
 	s.h.SetExtra(newExtra)
+	s.extraErr = nil
+	if len(newExtra) > MaxExtraDataSize {
+		s.extraErr = errors.Wrapf(ErrExtraTooLarge,
+			"%d bytes, limit %d", len(newExtra), MaxExtraDataSize)
+	}
+	return s
+}
+
+// UncheckedExtra sets the extra data field of this block, like Extra, but
+// without limiting its size.  It is meant for genesis headers, whose extra
+// data is not subject to the protocol limit.
+//
+// It stores a copy; the caller may freely modify the original.
+func (s HeaderFieldSetter) UncheckedExtra(newExtra []byte) HeaderFieldSetter {
+	s.h.SetExtra(newExtra)
+	s.extraErr = nil
 	return s
 }
 
@@ -162,11 +213,72 @@ func (s HeaderFieldSetter) Vdf(newVdf []byte) HeaderFieldSetter {
 	return s
 }
 
+// VRF sets the output of the VRF for the epoch, like Vrf, but setting it on a
+// header version without randomness (v0) is an error, reported by the
+// terminal Header (which panics) or HeaderChecked call, instead of a logged
+// warning.
+//
+// It stores a copy; the caller may freely modify the original.
+func (s HeaderFieldSetter) VRF(newVrf []byte) HeaderFieldSetter {
+	if s.checkRandomness("VRF") {
+		s.h.SetVrf(newVrf)
+	}
+	return s
+}
+
+// VDF sets the output of the VDF for the epoch, like Vdf, but setting it on a
+// header version without randomness (v0) is an error, reported by the
+// terminal Header (which panics) or HeaderChecked call, instead of a logged
+// warning.
+//
+// It stores a copy; the caller may freely modify the original.
+func (s HeaderFieldSetter) VDF(newVdf []byte) HeaderFieldSetter {
+	if s.checkRandomness("VDF") {
+		s.h.SetVdf(newVdf)
+	}
+	return s
+}
+
+// checkRandomness tells whether the header version carries randomness, and
+// records an error for the named field otherwise.
+func (s *HeaderFieldSetter) checkRandomness(field string) bool {
+	if v, _ := headerVersionOf(s.h.Header); v.features.randomness {
+		return true
+	}
+	if s.randomnessErr == nil {
+		s.randomnessErr = errors.Wrapf(ErrRandomnessNotSupported,
+			"cannot set %s of %s header", field, s.h.Version())
+	}
+	return false
+}
+
 // ShardState sets the RLP-encoded form of shard state
 //
 // It stores a copy; the caller may freely modify the original.
 func (s HeaderFieldSetter) ShardState(newShardState []byte) HeaderFieldSetter {
 	s.h.SetShardState(newShardState)
+	s.shardStateErr = nil
+	return s
+}
+
+// ShardStateStruct sets the shard state to the RLP encoding of the given
+// shard state, in the staking format that DecodedShardState decodes; a nil
+// state clears the shard state.  Pre-staking headers, which carry the legacy
+// format, should use ShardState with shard.EncodeWrapper instead.
+//
+// An encoding failure is reported by the terminal Header (which panics) or
+// HeaderChecked call.
+func (s HeaderFieldSetter) ShardStateStruct(newShardState *shard.State) HeaderFieldSetter {
+	var encoded []byte
+	s.shardStateErr = nil
+	if newShardState != nil {
+		var err error
+		encoded, err = shard.EncodeWrapper(*newShardState, true)
+		if err != nil {
+			s.shardStateErr = errors.Wrap(err, "cannot encode shard state")
+		}
+	}
+	s.h.SetShardState(encoded)
 	return s
 }
 
@@ -179,8 +291,55 @@ func (s HeaderFieldSetter) CrossLinks(newCrossLinks []byte) HeaderFieldSetter {
 	return s
 }
 
+// BaseFee sets the base fee per gas of transactions in the block.  Header
+// versions before v4 do not carry a base fee and ignore nil.
+//
+// It stores a copy; the caller may freely modify the original.
+func (s HeaderFieldSetter) BaseFee(newBaseFee *big.Int) HeaderFieldSetter {
+	s.h.SetBaseFee(newBaseFee)
+	return s
+}
+
 // Header returns the header whose fields have been set.  Call this at the end
 // of a field setter chain.
+//
+// Unlike HeaderChecked, Header does not limit the extra data size; use
+// ValidateExtraSize or Validate for that.  If a parent context has been
+// given, Header applies the default block number and panics if the epoch is
+// lower than that of the parent, which indicates a programming error in the
+// caller; use HeaderChecked to get an error instead.
 func (s HeaderFieldSetter) Header() *Header {
-	return s.h
+	s.extraErr = nil
+	h, err := s.HeaderChecked()
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// HeaderChecked is like Header, but returns an error instead of panicking,
+// and also fails if Extra was given data longer than MaxExtraDataSize.  The
+// error wraps ErrExtraTooLarge, ErrRandomnessNotSupported, or
+// ErrEpochDecreased, or reports that ShardStateStruct could not encode its
+// shard state.
+func (s HeaderFieldSetter) HeaderChecked() (*Header, error) {
+	if s.extraErr != nil {
+		return nil, s.extraErr
+	}
+	if s.shardStateErr != nil {
+		return nil, s.shardStateErr
+	}
+	if s.randomnessErr != nil {
+		return nil, s.randomnessErr
+	}
+	if s.parent != nil {
+		if !s.numberSet {
+			s.h.SetNumber(new(big.Int).Add(s.parent.Number(), big.NewInt(1)))
+		}
+		if epoch, parentEpoch := s.h.Epoch(), s.parent.Epoch(); epoch.Cmp(parentEpoch) < 0 {
+			return nil, errors.Wrapf(ErrEpochDecreased,
+				"epoch=%v, parent.Epoch()=%v", epoch, parentEpoch)
+		}
+	}
+	return s.h, nil
 }
@@ -0,0 +1,254 @@
+package block
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/shard"
+)
+
+func TestHeaderFieldSetter_ParentContext(t *testing.T) {
+	parent := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).Epoch(big.NewInt(5)).Header()
+
+	t.Run("DefaultNumber", func(t *testing.T) {
+		h := (&Header{Header: v3.NewHeader()}).With().
+			ParentContext(parent).Epoch(big.NewInt(5)).Header()
+		if h.Number().Cmp(big.NewInt(101)) != 0 {
+			t.Errorf("Number() = %v, want 101", h.Number())
+		}
+	})
+	t.Run("ExplicitNumber", func(t *testing.T) {
+		h := (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(200)).ParentContext(parent).Epoch(big.NewInt(6)).Header()
+		if h.Number().Cmp(big.NewInt(200)) != 0 {
+			t.Errorf("Number() = %v, want 200", h.Number())
+		}
+	})
+	t.Run("LowerEpoch", func(t *testing.T) {
+		defer func() {
+			err, _ := recover().(error)
+			if errors.Cause(err) != ErrEpochDecreased {
+				t.Errorf("Header() panicked with %v, want %v", err, ErrEpochDecreased)
+			}
+		}()
+		(&Header{Header: v3.NewHeader()}).With().
+			ParentContext(parent).Epoch(big.NewInt(4)).Header()
+	})
+	t.Run("Unconstrained", func(t *testing.T) {
+		h := (&Header{Header: v3.NewHeader()}).With().Epoch(big.NewInt(4)).Header()
+		if h.Number().Sign() != 0 || h.Epoch().Cmp(big.NewInt(4)) != 0 {
+			t.Errorf("Header() = number %v epoch %v, want 0 and 4", h.Number(), h.Epoch())
+		}
+	})
+}
+
+func TestHeaderFieldSetter_Extra(t *testing.T) {
+	oversized := make([]byte, MaxExtraDataSize+1)
+
+	t.Run("Header", func(t *testing.T) {
+		h := (&Header{Header: v3.NewHeader()}).With().Extra(oversized).Header()
+		if len(h.Extra()) != len(oversized) {
+			t.Errorf("len(Extra()) = %d, want %d", len(h.Extra()), len(oversized))
+		}
+		if err := h.ValidateExtraSize(MaxExtraDataSize, false); errors.Cause(err) != ErrExtraTooLarge {
+			t.Errorf("ValidateExtraSize() error = %v, want %v", err, ErrExtraTooLarge)
+		}
+	})
+	t.Run("HeaderChecked", func(t *testing.T) {
+		h, err := (&Header{Header: v3.NewHeader()}).With().Extra(oversized).HeaderChecked()
+		if h != nil || errors.Cause(err) != ErrExtraTooLarge {
+			t.Errorf("HeaderChecked() = %v, %v; want nil, %v", h, err, ErrExtraTooLarge)
+		}
+	})
+	t.Run("AtLimit", func(t *testing.T) {
+		h, err := (&Header{Header: v3.NewHeader()}).With().
+			Extra(make([]byte, MaxExtraDataSize)).HeaderChecked()
+		if err != nil || len(h.Extra()) != MaxExtraDataSize {
+			t.Errorf("HeaderChecked() = %v, %v", h, err)
+		}
+	})
+	t.Run("Replaced", func(t *testing.T) {
+		_, err := (&Header{Header: v3.NewHeader()}).With().
+			Extra(oversized).Extra([]byte("short")).HeaderChecked()
+		if err != nil {
+			t.Errorf("HeaderChecked() error = %v", err)
+		}
+	})
+	t.Run("Unchecked", func(t *testing.T) {
+		h := (&Header{Header: v3.NewHeader()}).With().UncheckedExtra(oversized).Header()
+		if len(h.Extra()) != len(oversized) {
+			t.Errorf("len(Extra()) = %d, want %d", len(h.Extra()), len(oversized))
+		}
+	})
+}
+
+func TestHeader_Patch(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).
+		ShardID(1).
+		GasLimit(8000000).
+		Extra([]byte("template")).
+		Header()
+	oldHash := h.Hash()
+	root := common.HexToHash("0x1234")
+	got := h.Patch(func(s HeaderFieldSetter) HeaderFieldSetter {
+		return s.GasUsed(21000).Root(root)
+	})
+	if got != h {
+		t.Errorf("Patch() returned %p, want the receiver %p", got, h)
+	}
+	if h.GasUsed() != 21000 || h.Root() != root {
+		t.Errorf("patched GasUsed, Root = %d, %x; want 21000, %x",
+			h.GasUsed(), h.Root(), root)
+	}
+	if h.Number().Cmp(big.NewInt(100)) != 0 || h.ShardID() != 1 ||
+		h.GasLimit() != 8000000 || string(h.Extra()) != "template" {
+		t.Errorf("Patch() did not preserve unset fields: %v", h)
+	}
+	if h.Hash() == oldHash || h.Hash() != h.HashNoCache() {
+		t.Errorf("Hash() = %x after patch, want %x", h.Hash(), h.HashNoCache())
+	}
+}
+
+func TestHeader_NewChild(t *testing.T) {
+	parent := (&Header{Header: v3.NewHeader()}).With().
+		ParentHash(common.HexToHash("0x1234")).
+		ShardID(2).
+		Number(big.NewInt(100)).
+		Epoch(big.NewInt(5)).
+		Time(big.NewInt(1000)).
+		Header()
+	child := parent.NewChild().Time(big.NewInt(1002)).Header()
+	if child.Version() != parent.Version() {
+		t.Errorf("child version = %s, want %s", child.Version(), parent.Version())
+	}
+	if child.ParentHash() != parent.Hash() {
+		t.Errorf("child ParentHash() = %x, want %x", child.ParentHash(), parent.Hash())
+	}
+	if child.ShardID() != 2 || child.Number().Cmp(big.NewInt(101)) != 0 ||
+		child.Epoch().Cmp(big.NewInt(5)) != 0 || child.Time().Cmp(big.NewInt(1002)) != 0 {
+		t.Errorf("child shard, number, epoch, time = %d, %v, %v, %v; want 2, 101, 5, 1002",
+			child.ShardID(), child.Number(), child.Epoch(), child.Time())
+	}
+	if err := child.ValidateAgainstParent(parent); err != nil {
+		t.Errorf("ValidateAgainstParent() error = %v", err)
+	}
+
+	next := parent.NewChild().Epoch(big.NewInt(6)).Header()
+	if next.Epoch().Cmp(big.NewInt(6)) != 0 {
+		t.Errorf("overridden child Epoch() = %v, want 6", next.Epoch())
+	}
+	if _, err := parent.NewChild().Epoch(big.NewInt(4)).HeaderChecked(); errors.Cause(err) != ErrEpochDecreased {
+		t.Errorf("HeaderChecked() of child with lower epoch error = %v, want %v", err, ErrEpochDecreased)
+	}
+}
+
+func TestHeaderFieldSetter_ShardStateStruct(t *testing.T) {
+	want := &shard.State{
+		Epoch: big.NewInt(3),
+		Shards: []shard.Committee{{
+			ShardID: 1,
+			Slots: shard.SlotList{{
+				EcdsaAddress: common.HexToAddress("0x1234"),
+			}},
+		}},
+	}
+	h, err := (&Header{Header: v3.NewHeader()}).With().ShardStateStruct(want).HeaderChecked()
+	if err != nil {
+		t.Fatalf("HeaderChecked() error = %v", err)
+	}
+	encoded, err := shard.EncodeWrapper(*want, true)
+	if err != nil {
+		t.Fatalf("cannot encode shard state: %v", err)
+	}
+	if !bytes.Equal(h.ShardState(), encoded) {
+		t.Errorf("ShardState() = %x, want %x", h.ShardState(), encoded)
+	}
+	got, err := h.DecodedShardState()
+	if err != nil {
+		t.Fatalf("DecodedShardState() error = %v", err)
+	}
+	if got.Epoch.Cmp(want.Epoch) != 0 || len(got.Shards) != 1 ||
+		got.Shards[0].ShardID != 1 || len(got.Shards[0].Slots) != 1 ||
+		got.Shards[0].Slots[0].EcdsaAddress != want.Shards[0].Slots[0].EcdsaAddress {
+		t.Errorf("DecodedShardState() = %v, want %v", got, want)
+	}
+
+	h = h.Patch(func(s HeaderFieldSetter) HeaderFieldSetter { return s.ShardStateStruct(nil) })
+	if _, err := h.DecodedShardState(); err != ErrNoShardState {
+		t.Errorf("DecodedShardState() after clearing error = %v, want %v", err, ErrNoShardState)
+	}
+}
+
+func TestHeaderFieldSetter_VRF_VDF(t *testing.T) {
+	vrf, vdf := []byte{1, 2, 3}, []byte{4, 5, 6}
+	h, err := (&Header{Header: v3.NewHeader()}).With().VRF(vrf).VDF(vdf).HeaderChecked()
+	if err != nil {
+		t.Fatalf("HeaderChecked() error = %v", err)
+	}
+	if !bytes.Equal(h.Vrf(), vrf) || !bytes.Equal(h.Vdf(), vdf) {
+		t.Errorf("Vrf(), Vdf() = %x, %x; want %x, %x", h.Vrf(), h.Vdf(), vrf, vdf)
+	}
+
+	for name, set := range map[string]func(HeaderFieldSetter) HeaderFieldSetter{
+		"VRF": func(s HeaderFieldSetter) HeaderFieldSetter { return s.VRF(vrf) },
+		"VDF": func(s HeaderFieldSetter) HeaderFieldSetter { return s.VDF(vdf) },
+	} {
+		_, err := set((&Header{Header: v0.NewHeader()}).With()).HeaderChecked()
+		if errors.Cause(err) != ErrRandomnessNotSupported {
+			t.Errorf("%s on v0: HeaderChecked() error = %v, want %v",
+				name, err, ErrRandomnessNotSupported)
+		}
+	}
+}
+
+func TestHeaderFieldSetter_BaseFee(t *testing.T) {
+	baseFee := big.NewInt(100)
+	h := (&Header{Header: v4.NewHeader()}).With().BaseFee(baseFee).Header()
+	baseFee.SetInt64(200)
+	if got := h.BaseFee(); got == nil || got.Int64() != 100 {
+		t.Errorf("BaseFee() = %v, want 100", got)
+	}
+	h, err := (&Header{Header: v4.NewHeader()}).WithValidated().
+		ParentHash(common.HexToHash("0x1234")).
+		Number(big.NewInt(10)).
+		Epoch(big.NewInt(2)).
+		ShardID(1).
+		BaseFee(big.NewInt(300)).
+		Header()
+	if err != nil {
+		t.Fatalf("ValidatedHeaderBuilder.Header() error = %v", err)
+	}
+	if got := h.BaseFee(); got == nil || got.Int64() != 300 {
+		t.Errorf("BaseFee() from ValidatedHeaderBuilder = %v, want 300", got)
+	}
+
+	var buf bytes.Buffer
+	logger := utils.Logger()
+	saved := *logger
+	*logger = zerolog.New(&buf)
+	defer func() { *logger = saved }()
+	for _, v := range headerVersions {
+		if v.features.baseFee {
+			continue
+		}
+		h := (&Header{Header: v.factory()}).With().BaseFee(nil).Header()
+		if h.BaseFee() != nil {
+			t.Errorf("%s: BaseFee() = %v, want nil", v.name, h.BaseFee())
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%s: setting nil base fee logged %s", v.name, buf.String())
+			buf.Reset()
+		}
+	}
+}
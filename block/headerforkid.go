@@ -0,0 +1,44 @@
+package block
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ForkID returns the fork identifier of the chain as of this header, for
+// peers to compare during handshake, following EIP-2124: the CRC32 checksum
+// of the genesis hash, updated with the big-endian number of each fork block
+// the header has reached.  Fork blocks may be given in any order; duplicates
+// and forks at block 0, which every chain has passed, are ignored.
+//
+// Forks beyond the header do not contribute, so two peers on compatible
+// chains at different heights may report different IDs; EIP-2124 resolves
+// this by also exchanging the next fork block, which is up to the caller.
+func (h *Header) ForkID(genesisHash common.Hash, forkBlocks []uint64) ([4]byte, error) {
+	var id [4]byte
+	head, err := h.NumberU64()
+	if err != nil {
+		return id, err
+	}
+	forks := append([]uint64(nil), forkBlocks...)
+	sort.Slice(forks, func(i, j int) bool { return forks[i] < forks[j] })
+	sum := crc32.ChecksumIEEE(genesisHash[:])
+	var prev uint64
+	for _, fork := range forks {
+		if fork > head {
+			break
+		}
+		if fork == prev {
+			continue
+		}
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], fork)
+		sum = crc32.Update(sum, crc32.IEEETable, b[:])
+		prev = fork
+	}
+	binary.BigEndian.PutUint32(id[:], sum)
+	return id, nil
+}
@@ -0,0 +1,56 @@
+package block
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_ForkID(t *testing.T) {
+	genesis := common.HexToHash("0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3")
+	h := (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(1000)).Header()
+	forkID := func(h *Header, forks ...uint64) [4]byte {
+		t.Helper()
+		id, err := h.ForkID(genesis, forks)
+		if err != nil {
+			t.Fatalf("ForkID(%v) error = %v", forks, err)
+		}
+		return id
+	}
+
+	noForks := forkID(h)
+	var want [4]byte
+	binary.BigEndian.PutUint32(want[:], crc32.ChecksumIEEE(genesis[:]))
+	if noForks != want {
+		t.Errorf("ForkID() without forks = %x, want CRC32 of genesis hash %x", noForks, want)
+	}
+
+	pastFork := forkID(h, 500)
+	if pastFork == noForks {
+		t.Errorf("ForkID() with past fork = %x, same as without forks", pastFork)
+	}
+	if got := forkID(h, 500, 0, 500); got != pastFork {
+		t.Errorf("ForkID() with genesis and duplicate forks = %x, want %x", got, pastFork)
+	}
+	if got := forkID(h, 1000); got == noForks || got == pastFork {
+		t.Errorf("ForkID() with fork at header = %x, want distinct ID", got)
+	}
+
+	// A future fork does not change the ID until the chain reaches it.
+	if got := forkID(h, 500, 2000); got != pastFork {
+		t.Errorf("ForkID() with future fork = %x, want %x", got, pastFork)
+	}
+	later := (&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(2000)).Header()
+	if got := forkID(later, 2000, 500); got == pastFork || got == noForks {
+		t.Errorf("ForkID() past the future fork = %x, want distinct ID", got)
+	}
+
+	if _, err := (*Header)(nil).ForkID(genesis, nil); err != ErrHeaderIsNil {
+		t.Errorf("ForkID() on nil header error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
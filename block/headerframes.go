@@ -0,0 +1,61 @@
+package block
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCorruptHeaderFrames is returned by HeadersFromBytes when the input is not
+// a valid sequence of length-prefixed headers.
+var ErrCorruptHeaderFrames = errors.New("corrupt header frames")
+
+// HeadersToBytes encodes the given headers into a single byte slice, framing
+// each header's tagged RLP encoding with a uvarint length prefix.  Headers may
+// be of different versions.  Use HeadersFromBytes to decode the result.
+func HeadersToBytes(headers []*Header) ([]byte, error) {
+	var out []byte
+	var prefix [binary.MaxVarintLen64]byte
+	for i, h := range headers {
+		if isNilHeader(h) {
+			return nil, errors.Wrapf(ErrHeaderIsNil, "header #%d", i)
+		}
+		b, err := h.Bytes()
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot encode header #%d", i)
+		}
+		n := binary.PutUvarint(prefix[:], uint64(len(b)))
+		out = append(out, prefix[:n]...)
+		out = append(out, b...)
+	}
+	return out, nil
+}
+
+// HeadersFromBytes decodes headers encoded by HeadersToBytes.  A truncated or
+// corrupt frame is an error that wraps ErrCorruptHeaderFrames and names the
+// byte offset of the frame.
+func HeadersFromBytes(b []byte) ([]*Header, error) {
+	headers := []*Header{}
+	for offset := 0; offset < len(b); {
+		size, n := binary.Uvarint(b[offset:])
+		if n <= 0 {
+			return nil, errors.Wrapf(ErrCorruptHeaderFrames,
+				"bad length prefix of header #%d at offset %d", len(headers), offset)
+		}
+		start := offset + n
+		if size > uint64(len(b)-start) {
+			return nil, errors.Wrapf(ErrCorruptHeaderFrames,
+				"header #%d at offset %d: length %d exceeds remaining %d bytes",
+				len(headers), offset, size, len(b)-start)
+		}
+		end := start + int(size)
+		h, err := HeaderFromBytes(b[start:end])
+		if err != nil {
+			return nil, errors.Wrapf(ErrCorruptHeaderFrames,
+				"cannot decode header #%d at offset %d: %v", len(headers), offset, err)
+		}
+		headers = append(headers, h)
+		offset = end
+	}
+	return headers, nil
+}
@@ -0,0 +1,89 @@
+package block
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestHeadersToBytes_RoundTrip(t *testing.T) {
+	mixed := mixedVersionHeaders()
+	tests := []struct {
+		name    string
+		headers []*Header
+	}{
+		{"Empty", nil},
+		{"One", mixed[:1]},
+		{"MixedVersions", mixed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := HeadersToBytes(tt.headers)
+			if err != nil {
+				t.Fatalf("HeadersToBytes() error = %v", err)
+			}
+			got, err := HeadersFromBytes(b)
+			if err != nil {
+				t.Fatalf("HeadersFromBytes() error = %v", err)
+			}
+			if len(got) != len(tt.headers) {
+				t.Fatalf("HeadersFromBytes() got %d headers, want %d",
+					len(got), len(tt.headers))
+			}
+			for i, want := range tt.headers {
+				if reflect.TypeOf(got[i].Header) != reflect.TypeOf(want.Header) {
+					t.Errorf("header #%d got type %T, want %T", i, got[i].Header, want.Header)
+				}
+				if !got[i].Equal(want) {
+					t.Errorf("header #%d got %v, want %v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHeadersToBytes_NilHeader(t *testing.T) {
+	_, err := HeadersToBytes([]*Header{mixedVersionHeaders()[0], nil})
+	if errors.Cause(err) != ErrHeaderIsNil {
+		t.Errorf("HeadersToBytes() error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
+
+func TestHeadersFromBytes_Corrupt(t *testing.T) {
+	headers := mixedVersionHeaders()
+	b, err := HeadersToBytes(headers)
+	if err != nil {
+		t.Fatalf("HeadersToBytes() error = %v", err)
+	}
+	first, err := HeadersToBytes(headers[:1])
+	if err != nil {
+		t.Fatalf("HeadersToBytes() error = %v", err)
+	}
+	corrupt := append([]byte(nil), b...)
+	_, prefixLen := binary.Uvarint(b[len(first):])
+	corrupt[len(first)+prefixLen] ^= 0xff
+	tests := []struct {
+		name       string
+		b          []byte
+		wantOffset string
+	}{
+		{"Truncated", b[:len(b)-1], "offset "},
+		{"BadLengthPrefix", append(append([]byte(nil), first...), 0xff), "offset " + strconv.Itoa(len(first))},
+		{"CorruptSecondHeader", corrupt, "offset " + strconv.Itoa(len(first))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := HeadersFromBytes(tt.b)
+			if errors.Cause(err) != ErrCorruptHeaderFrames {
+				t.Fatalf("HeadersFromBytes() error = %v, want %v", err, ErrCorruptHeaderFrames)
+			}
+			if !strings.Contains(err.Error(), tt.wantOffset) {
+				t.Errorf("HeadersFromBytes() error %q does not mention %q", err, tt.wantOffset)
+			}
+		})
+	}
+}
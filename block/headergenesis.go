@@ -0,0 +1,33 @@
+package block
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+// NewGenesisHeader returns a v3 genesis header for the given shard and epoch:
+// block number 0, zero parent hash, empty extra data, and empty trie roots
+// for the state, transactions, and receipts.  All other fields are zero.  A
+// nil epoch stands for epoch 0.
+//
+// The result depends only on the arguments, so equal arguments yield headers
+// with equal hashes.
+func NewGenesisHeader(shardID uint32, epoch *big.Int) *Header {
+	if epoch == nil {
+		epoch = new(big.Int)
+	}
+	return (&Header{Header: v3.NewHeader()}).With().
+		Number(new(big.Int)).
+		Epoch(epoch).
+		ShardID(shardID).
+		Root(types.EmptyRootHash).
+		TxHash(types.EmptyRootHash).
+		ReceiptHash(types.EmptyRootHash).
+		OutgoingReceiptHash(types.EmptyRootHash).
+		IncomingReceiptHash(types.EmptyRootHash).
+		UncheckedExtra([]byte{}).
+		Header()
+}
@@ -0,0 +1,26 @@
+package block
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNewGenesisHeader(t *testing.T) {
+	h := NewGenesisHeader(1, big.NewInt(3))
+	if !h.IsGenesis() {
+		t.Errorf("IsGenesis() = false for %v", h)
+	}
+	if h.Version() != "v3" || h.ShardID() != 1 || h.Epoch().Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("NewGenesisHeader(1, 3) = %v", h)
+	}
+	if again := NewGenesisHeader(1, big.NewInt(3)); again.Hash() != h.Hash() {
+		t.Errorf("hashes of identical genesis headers differ: %s != %s",
+			again.Hash().Hex(), h.Hash().Hex())
+	}
+	if other := NewGenesisHeader(2, big.NewInt(3)); other.Hash() == h.Hash() {
+		t.Error("genesis headers of different shards have equal hashes")
+	}
+	if NewGenesisHeader(1, nil).Hash() != NewGenesisHeader(1, new(big.Int)).Hash() {
+		t.Error("nil epoch is not epoch 0")
+	}
+}
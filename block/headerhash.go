@@ -0,0 +1,199 @@
+package block
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// The setters below shadow those of the embedded versioned header so that
+// every mutation made through a Header invalidates its cached hash.  Mutating
+// the embedded blockif.Header directly bypasses this; call HashNoCache, or
+// reassign the mutated field via Header, in that case.
+
+// invalidateHash discards the cached hash, if any.
+func (h *Header) invalidateHash() {
+	h.cachedHash.Store((*common.Hash)(nil))
+}
+
+// SetParentHash sets the parent hash field.
+func (h *Header) SetParentHash(newParentHash common.Hash) {
+	h.Header.SetParentHash(newParentHash)
+	h.invalidateHash()
+}
+
+// SetCoinbase sets the coinbase address field.
+func (h *Header) SetCoinbase(newCoinbase common.Address) {
+	h.Header.SetCoinbase(newCoinbase)
+	h.invalidateHash()
+}
+
+// SetRoot sets the state trie root hash field.
+func (h *Header) SetRoot(newRoot common.Hash) {
+	h.Header.SetRoot(newRoot)
+	h.invalidateHash()
+}
+
+// SetTxHash sets the transaction trie root hash field.
+func (h *Header) SetTxHash(newTxHash common.Hash) {
+	h.Header.SetTxHash(newTxHash)
+	h.invalidateHash()
+}
+
+// SetReceiptHash sets the same-shard transaction receipt trie hash.
+func (h *Header) SetReceiptHash(newReceiptHash common.Hash) {
+	h.Header.SetReceiptHash(newReceiptHash)
+	h.invalidateHash()
+}
+
+// SetOutgoingReceiptHash sets the egress transaction receipt trie hash.
+func (h *Header) SetOutgoingReceiptHash(newOutgoingReceiptHash common.Hash) {
+	h.Header.SetOutgoingReceiptHash(newOutgoingReceiptHash)
+	h.invalidateHash()
+}
+
+// SetIncomingReceiptHash sets the ingress transaction receipt trie hash.
+func (h *Header) SetIncomingReceiptHash(newIncomingReceiptHash common.Hash) {
+	h.Header.SetIncomingReceiptHash(newIncomingReceiptHash)
+	h.invalidateHash()
+}
+
+// SetBloom sets the smart contract log Bloom filter for this block.
+func (h *Header) SetBloom(newBloom types.Bloom) {
+	h.Header.SetBloom(newBloom)
+	h.invalidateHash()
+}
+
+// SetNumber sets the block number.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetNumber(newNumber *big.Int) {
+	h.Header.SetNumber(newNumber)
+	h.invalidateHash()
+}
+
+// SetGasLimit sets the gas limit for transactions in this block.
+func (h *Header) SetGasLimit(newGasLimit uint64) {
+	h.Header.SetGasLimit(newGasLimit)
+	h.invalidateHash()
+}
+
+// SetGasUsed sets the amount of gas used by transactions in this block.
+func (h *Header) SetGasUsed(newGasUsed uint64) {
+	h.Header.SetGasUsed(newGasUsed)
+	h.invalidateHash()
+}
+
+// SetTime sets the UNIX timestamp of this block.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetTime(newTime *big.Int) {
+	h.Header.SetTime(newTime)
+	h.invalidateHash()
+}
+
+// SetExtra sets the extra data field of this block.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetExtra(newExtra []byte) {
+	h.Header.SetExtra(newExtra)
+	h.invalidateHash()
+}
+
+// SetMixDigest sets the mixhash of this block.
+func (h *Header) SetMixDigest(newMixDigest common.Hash) {
+	h.Header.SetMixDigest(newMixDigest)
+	h.invalidateHash()
+}
+
+// SetViewID sets the view ID in which the block was originally proposed.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetViewID(newViewID *big.Int) {
+	h.Header.SetViewID(newViewID)
+	h.invalidateHash()
+}
+
+// SetEpoch sets the epoch number of this block.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetEpoch(newEpoch *big.Int) {
+	h.Header.SetEpoch(newEpoch)
+	h.invalidateHash()
+}
+
+// SetShardID sets the shard ID to which this block belongs.
+func (h *Header) SetShardID(newShardID uint32) {
+	h.Header.SetShardID(newShardID)
+	h.invalidateHash()
+}
+
+// SetLastCommitSignature sets the FBFT commit group signature for the last
+// block.
+func (h *Header) SetLastCommitSignature(newLastCommitSignature [96]byte) {
+	h.Header.SetLastCommitSignature(newLastCommitSignature)
+	h.invalidateHash()
+}
+
+// SetLastCommitBitmap sets the signatory bitmap of the previous block.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetLastCommitBitmap(newLastCommitBitmap []byte) {
+	h.Header.SetLastCommitBitmap(newLastCommitBitmap)
+	h.invalidateHash()
+}
+
+// SetShardStateHash sets the shard state hash.
+func (h *Header) SetShardStateHash(newShardStateHash common.Hash) {
+	h.Header.SetShardStateHash(newShardStateHash)
+	h.invalidateHash()
+}
+
+// SetVrf sets the output of the VRF for the epoch.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetVrf(newVrf []byte) {
+	h.Header.SetVrf(newVrf)
+	h.invalidateHash()
+}
+
+// SetVdf sets the output of the VDF for the epoch.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetVdf(newVdf []byte) {
+	h.Header.SetVdf(newVdf)
+	h.invalidateHash()
+}
+
+// SetShardState sets the RLP-encoded form of shard state
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetShardState(newShardState []byte) {
+	h.Header.SetShardState(newShardState)
+	h.invalidateHash()
+}
+
+// SetCrossLinks sets the RLP-encoded form of non-beacon block headers chosen to
+// be canonical by the beacon committee.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetCrossLinks(newCrossLinks []byte) {
+	h.Header.SetCrossLinks(newCrossLinks)
+	h.invalidateHash()
+}
+
+// SetSlashes sets the RLP-encoded form of slashes
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetSlashes(newSlashes []byte) {
+	h.Header.SetSlashes(newSlashes)
+	h.invalidateHash()
+}
+
+// SetBaseFee sets the base fee per gas of transactions in this block.
+//
+// It stores a copy; the caller may freely modify the original.
+func (h *Header) SetBaseFee(newBaseFee *big.Int) {
+	h.Header.SetBaseFee(newBaseFee)
+	h.invalidateHash()
+}
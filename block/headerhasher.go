@@ -0,0 +1,57 @@
+package block
+
+import (
+	"bytes"
+	"hash"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/taggedrlp"
+	"golang.org/x/crypto/sha3"
+)
+
+// keccakState is a Keccak256 hash that can be squeezed with Read, which,
+// unlike Sum, does not copy the hash state.
+type keccakState interface {
+	hash.Hash
+	Read([]byte) (int, error)
+}
+
+// headerHashState is reusable scratch state for hashing one header.
+type headerHashState struct {
+	buf    bytes.Buffer
+	hasher keccakState
+	sum    common.Hash
+}
+
+var headerHashStatePool = sync.Pool{
+	New: func() interface{} {
+		return &headerHashState{hasher: sha3.NewLegacyKeccak256().(keccakState)}
+	},
+}
+
+// hashHeaderEncoding returns the Keccak256 hash of the tagged RLP encoding of
+// the header, or the error encountered while encoding it.  It is safe for
+// concurrent use.
+func hashHeaderEncoding(h *Header) (common.Hash, error) {
+	return hashHeaderEncodingWithRegistry(h, HeaderRegistry)
+}
+
+// hashHeaderEncodingWithRegistry is like hashHeaderEncoding, but encodes the
+// header with the given registry.  The encoding goes into a pooled buffer, so
+// that hashing does not allocate one per call.
+func hashHeaderEncodingWithRegistry(h *Header, reg *taggedrlp.Registry) (common.Hash, error) {
+	if isNilHeader(h) {
+		return common.Hash{}, ErrHeaderIsNil
+	}
+	st := headerHashStatePool.Get().(*headerHashState)
+	defer headerHashStatePool.Put(st)
+	st.buf.Reset()
+	st.hasher.Reset()
+	if err := h.EncodeRLPWithRegistry(&st.buf, reg); err != nil {
+		return common.Hash{}, err
+	}
+	st.hasher.Write(st.buf.Bytes())
+	st.hasher.Read(st.sum[:])
+	return st.sum, nil
+}
@@ -0,0 +1,145 @@
+package block
+
+import (
+	"io"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/harmony-one/taggedrlp"
+
+	blockif "github.com/harmony-one/harmony/block/interface"
+	v3 "github.com/harmony-one/harmony/block/v3"
+	"github.com/harmony-one/harmony/crypto/hash"
+)
+
+func TestHashHeaderEncoding(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		h := randomHeader(rnd)
+		b, err := h.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes() error = %v", err)
+		}
+		want := hash.Keccak256Hash(b)
+		got, err := hashHeaderEncoding(h)
+		if err != nil {
+			t.Fatalf("hashHeaderEncoding() error = %v", err)
+		}
+		if got != want || h.HashNoCache() != want || hash.FromRLP(h) != want {
+			t.Errorf("%s header: hashHeaderEncoding() = %x, want %x", h.Version(), got, want)
+		}
+	}
+	if _, err := hashHeaderEncoding(&Header{Header: unregisteredHeader{}}); err == nil {
+		t.Error("hashHeaderEncoding() of unregistered header succeeded")
+	}
+}
+
+// registryEncoder RLP-encodes a header with a given registry.
+type registryEncoder struct {
+	h   *Header
+	reg *taggedrlp.Registry
+}
+
+func (e registryEncoder) EncodeRLP(w io.Writer) error {
+	return e.h.EncodeRLPWithRegistry(w, e.reg)
+}
+
+func TestHashHeaderEncoding_AllVersions(t *testing.T) {
+	r := newHeaderRegistry()
+	factory := func() blockif.Header { return &customHeader{v3.NewHeader()} }
+	if err := r.register("side1", factory); err != nil {
+		t.Fatalf("register() error = %v", err)
+	}
+	for _, v := range r.versions {
+		h := (&Header{Header: v.factory()}).With().
+			Number(big.NewInt(100)).ShardID(1).Extra([]byte("extra")).Header()
+		got, err := hashHeaderEncodingWithRegistry(h, r.reg)
+		if err != nil {
+			t.Fatalf("%q header: hashHeaderEncodingWithRegistry() error = %v", v.tag, err)
+		}
+		if want := hash.FromRLP(registryEncoder{h, r.reg}); got != want {
+			t.Errorf("%q header: hashHeaderEncodingWithRegistry() = %x, want %x", v.tag, got, want)
+		}
+		if _, builtin := headerVersionsByTag[v.tag]; !builtin {
+			continue
+		}
+		got, err = hashHeaderEncoding(h)
+		if err != nil {
+			t.Fatalf("%q header: hashHeaderEncoding() error = %v", v.tag, err)
+		}
+		if want := hash.FromRLP(h); got != want {
+			t.Errorf("%q header: hashHeaderEncoding() = %x, want %x", v.tag, got, want)
+		}
+	}
+}
+
+func TestHeader_HashConcurrent(t *testing.T) {
+	h := randomHeader(rand.New(rand.NewSource(2)))
+	want := hash.FromRLP(h)
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if got := h.Hash(); got != want {
+					t.Errorf("Hash() = %x, want %x", got, want)
+					return
+				}
+				if got := h.HashNoCache(); got != want {
+					t.Errorf("HashNoCache() = %x, want %x", got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestHeader_HashMarshalConcurrent is meant to be run with -race: marshaling
+// a header must not read its hash cache while Hash stores into it.
+func TestHeader_HashMarshalConcurrent(t *testing.T) {
+	h := randomHeader(rand.New(rand.NewSource(4)))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				h.invalidateHash()
+				h.Hash()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, err := h.MarshalJSON(); err != nil {
+					t.Errorf("MarshalJSON() error = %v", err)
+					return
+				}
+				_ = h.String()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkHeader_HashViaRegistry hashes the tagged RLP encoding produced by
+// the registry, for comparison with BenchmarkHeader_HashNoCache.
+func BenchmarkHeader_HashViaRegistry(b *testing.B) {
+	h := randomHeader(rand.New(rand.NewSource(3)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hash.FromRLP(h)
+	}
+}
+
+func BenchmarkHeader_HashNoCache_Random(b *testing.B) {
+	h := randomHeader(rand.New(rand.NewSource(3)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.HashNoCache()
+	}
+}
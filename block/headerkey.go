@@ -0,0 +1,44 @@
+package block
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// HeaderKey identifies a block across shards by shard ID and block number.
+// It is comparable and may be used as a map key.
+type HeaderKey struct {
+	ShardID uint32
+	Number  uint64
+}
+
+// Key returns the shard-aware key of the header.  Block numbers beyond the
+// uint64 range are truncated, as by big.Int.Uint64.
+func (h *Header) Key() HeaderKey {
+	if isNilHeader(h) {
+		return HeaderKey{}
+	}
+	return HeaderKey{ShardID: h.ShardID(), Number: bigUint64OrZero(h.Number)}
+}
+
+// String returns the key in the form "<shard>:<number>", the same prefix as
+// the text form of the header.
+func (k HeaderKey) String() string {
+	return fmt.Sprintf("%d:%d", k.ShardID, k.Number)
+}
+
+// ParentKey identifies the parent of a block by the shard ID and the parent
+// hash of the block.  It is comparable and may be used as a map key.
+type ParentKey struct {
+	ShardID uint32
+	Hash    common.Hash
+}
+
+// ParentKey returns the key of the parent of the header.
+func (h *Header) ParentKey() ParentKey {
+	if isNilHeader(h) {
+		return ParentKey{}
+	}
+	return ParentKey{ShardID: h.ShardID(), Hash: h.ParentHash()}
+}
@@ -0,0 +1,50 @@
+package block
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_Key(t *testing.T) {
+	x := (&Header{Header: v3.NewHeader()}).With().
+		ShardID(1).Number(big.NewInt(100)).Extra([]byte("x")).Header()
+	y := (&Header{Header: v0.NewHeader()}).With().
+		ShardID(1).Number(big.NewInt(100)).Extra([]byte("y")).Header()
+	z := (&Header{Header: v3.NewHeader()}).With().
+		ShardID(2).Number(big.NewInt(100)).Header()
+	if x.Key() != y.Key() {
+		t.Errorf("keys of same shard and number differ: %v != %v", x.Key(), y.Key())
+	}
+	if x.Key() == z.Key() {
+		t.Errorf("keys of different shards are equal: %v", x.Key())
+	}
+	index := map[HeaderKey]*Header{x.Key(): x, z.Key(): z}
+	if index[y.Key()] != x || len(index) != 2 {
+		t.Errorf("map lookup by key failed: %v", index)
+	}
+	if got, want := x.Key().String(), "1:100"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestHeader_ParentKey(t *testing.T) {
+	parentHash := common.HexToHash("0x1234")
+	h := (&Header{Header: v3.NewHeader()}).With().
+		ShardID(2).Number(big.NewInt(100)).ParentHash(parentHash).Header()
+	got := h.ParentKey()
+	if got.ShardID != h.ShardID() || got.Hash != h.ParentHash() {
+		t.Errorf("ParentKey() = %+v, want {%d %x}", got, h.ShardID(), h.ParentHash())
+	}
+	if got != (ParentKey{ShardID: 2, Hash: parentHash}) {
+		t.Errorf("ParentKey() = %+v", got)
+	}
+	var nilHeader *Header
+	if got := nilHeader.ParentKey(); got != (ParentKey{}) {
+		t.Errorf("nil ParentKey() = %+v, want zero key", got)
+	}
+}
@@ -0,0 +1,103 @@
+package block
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// LightHeader is a projection of a header onto the few fields that light
+// clients need to follow the chain.
+//
+// The projection is lossy by design: heavy fields such as the shard state,
+// the last commit signature and bitmap, and cross-links are dropped, so a
+// LightHeader can neither be converted back into a Header nor have its hash
+// recomputed.  Hash carries the hash of the source header instead.
+type LightHeader struct {
+	Number     *big.Int
+	Hash       common.Hash
+	ParentHash common.Hash
+	Epoch      *big.Int
+	ShardID    uint32
+	Time       *big.Int
+	Root       common.Hash
+}
+
+// lightHeaderJSON is the JSON form of LightHeader.  Field names and encodings
+// match those of headerJSON, including the decimal strings for the epoch and
+// shard ID.
+type lightHeaderJSON struct {
+	Number     *hexutil.Big `json:"number"`
+	Hash       common.Hash  `json:"hash"`
+	ParentHash common.Hash  `json:"parentHash"`
+	Epoch      string       `json:"epoch"`
+	ShardID    string       `json:"shardID"`
+	Time       *hexutil.Big `json:"timestamp"`
+	Root       common.Hash  `json:"stateRoot"`
+}
+
+// Light returns the light projection of the header.
+func (h *Header) Light() LightHeader {
+	return LightHeader{
+		Number:     h.Number(),
+		Hash:       h.Hash(),
+		ParentHash: h.ParentHash(),
+		Epoch:      h.Epoch(),
+		ShardID:    h.ShardID(),
+		Time:       h.Time(),
+		Root:       h.Root(),
+	}
+}
+
+// MarshalJSON encodes the light header into JSON.
+func (lh LightHeader) MarshalJSON() ([]byte, error) {
+	var epoch string
+	if lh.Epoch != nil {
+		epoch = lh.Epoch.String()
+	}
+	return json.Marshal(lightHeaderJSON{
+		Number:     (*hexutil.Big)(lh.Number),
+		Hash:       lh.Hash,
+		ParentHash: lh.ParentHash,
+		Epoch:      epoch,
+		ShardID:    strconv.FormatUint(uint64(lh.ShardID), 10),
+		Time:       (*hexutil.Big)(lh.Time),
+		Root:       lh.Root,
+	})
+}
+
+// UnmarshalJSON decodes the JSON form produced by MarshalJSON.
+func (lh *LightHeader) UnmarshalJSON(data []byte) error {
+	var dec lightHeaderJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	var epoch *big.Int
+	if dec.Epoch != "" {
+		var ok bool
+		if epoch, ok = new(big.Int).SetString(dec.Epoch, 10); !ok {
+			return errors.Errorf("invalid epoch %#v", dec.Epoch)
+		}
+	}
+	var shardID uint64
+	if dec.ShardID != "" {
+		var err error
+		if shardID, err = strconv.ParseUint(dec.ShardID, 10, 32); err != nil {
+			return errors.Wrapf(err, "invalid shardID %#v", dec.ShardID)
+		}
+	}
+	*lh = LightHeader{
+		Number:     (*big.Int)(dec.Number),
+		Hash:       dec.Hash,
+		ParentHash: dec.ParentHash,
+		Epoch:      epoch,
+		ShardID:    uint32(shardID),
+		Time:       (*big.Int)(dec.Time),
+		Root:       dec.Root,
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package block
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_Light(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).
+		ParentHash(common.HexToHash("0x0102")).
+		Epoch(big.NewInt(7)).
+		ShardID(3).
+		Time(big.NewInt(1600000000)).
+		Root(common.HexToHash("0x0304")).
+		ShardState([]byte{0xc1, 0x01}).
+		LastCommitBitmap([]byte{0xff}).
+		Header()
+	lh := h.Light()
+	want := LightHeader{
+		Number:     big.NewInt(100),
+		Hash:       h.Hash(),
+		ParentHash: common.HexToHash("0x0102"),
+		Epoch:      big.NewInt(7),
+		ShardID:    3,
+		Time:       big.NewInt(1600000000),
+		Root:       common.HexToHash("0x0304"),
+	}
+	if !reflect.DeepEqual(lh, want) {
+		t.Errorf("Light() = %+v, want %+v", lh, want)
+	}
+	lh.Number.SetInt64(0)
+	if h.Number().Int64() != 100 {
+		t.Error("modifying Light().Number changed the source header")
+	}
+}
+
+func TestLightHeader_JSON(t *testing.T) {
+	lh := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).
+		Epoch(big.NewInt(7)).
+		ShardID(3).
+		Time(big.NewInt(1600000000)).
+		Header().
+		Light()
+	data, err := json.Marshal(lh)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	for _, heavy := range []string{"shardState", "lastCommitBitmap", "crossLinks"} {
+		if strings.Contains(string(data), heavy) {
+			t.Errorf("light header JSON %s contains %q", data, heavy)
+		}
+	}
+	var got LightHeader
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, lh) {
+		t.Errorf("JSON round trip = %+v, want %+v", got, lh)
+	}
+}
+
+func TestLightHeader_JSONMatchesHeader(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).
+		ParentHash(common.HexToHash("0x0102")).
+		Epoch(big.NewInt(1234)).
+		ShardID(3).
+		Time(big.NewInt(1600000000)).
+		Root(common.HexToHash("0x0304")).
+		Header()
+	full, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("Header MarshalJSON() error = %v", err)
+	}
+	light, err := json.Marshal(h.Light())
+	if err != nil {
+		t.Fatalf("LightHeader MarshalJSON() error = %v", err)
+	}
+	var fullFields, lightFields map[string]json.RawMessage
+	if err := json.Unmarshal(full, &fullFields); err != nil {
+		t.Fatalf("cannot parse header JSON: %v", err)
+	}
+	if err := json.Unmarshal(light, &lightFields); err != nil {
+		t.Fatalf("cannot parse light header JSON: %v", err)
+	}
+	for key, value := range lightFields {
+		if string(fullFields[key]) != string(value) {
+			t.Errorf("light header %#v = %s, header has %s", key, value, fullFields[key])
+		}
+	}
+	var got LightHeader
+	if err := json.Unmarshal(light, &got); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, h.Light()) {
+		t.Errorf("JSON round trip = %+v, want %+v", got, h.Light())
+	}
+}
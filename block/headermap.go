@@ -0,0 +1,56 @@
+package block
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ToMap returns the fields of the header as a map with the same keys as its
+// JSON form, for indexing into document stores without a JSON round trip.
+//
+// Values are native Go types: big integers as decimal strings, hashes,
+// addresses, and byte slices as 0x-prefixed hex strings, integers as uint64,
+// and absent optional fields as nil.  As in the JSON form, an absent base fee
+// is omitted.
+func (h *Header) ToMap() map[string]interface{} {
+	if isNilHeader(h) {
+		return nil
+	}
+	enc := reflect.ValueOf(newHeaderJSON(h))
+	m := make(map[string]interface{}, enc.NumField())
+	for i := 0; i < enc.NumField(); i++ {
+		field := enc.Type().Field(i)
+		value := enc.Field(i)
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			if strings.HasSuffix(field.Tag.Get("json"), ",omitempty") {
+				continue
+			}
+			m[headerJSONKey(field)] = nil
+			continue
+		}
+		m[headerJSONKey(field)] = headerMapValue(reflect.Indirect(value))
+	}
+	return m
+}
+
+// headerMapValue returns the native Go form of a non-pointer headerJSON field
+// value, as documented in ToMap.
+func headerMapValue(v reflect.Value) interface{} {
+	if v.Type().ConvertibleTo(bigIntType) {
+		n := v.Convert(bigIntType).Interface().(big.Int)
+		return n.String()
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return hexutil.Encode(b)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,74 @@
+package block
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
+)
+
+func TestHeader_ToMap(t *testing.T) {
+	headers := map[string]*Header{
+		"legacy": (&Header{Header: v0.NewHeader()}).With().
+			Number(big.NewInt(100)).Header(),
+		"v3": (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(100)).
+			ParentHash(common.HexToHash("0x1234")).
+			Extra([]byte{0xab}).
+			Epoch(big.NewInt(7)).
+			Header(),
+		"v4": (&Header{Header: v4.NewHeader()}).With().Number(big.NewInt(100)).Header(),
+	}
+	headers["v4"].SetBaseFee(big.NewInt(1000000000))
+	for name, h := range headers {
+		t.Run(name, func(t *testing.T) {
+			m := h.ToMap()
+			data, err := json.Marshal(h)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var jsonMap map[string]interface{}
+			if err := json.Unmarshal(data, &jsonMap); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if len(m) != len(jsonMap) {
+				t.Errorf("ToMap() has %d keys, JSON has %d", len(m), len(jsonMap))
+			}
+			for key := range jsonMap {
+				if _, ok := m[key]; !ok {
+					t.Errorf("ToMap() lacks JSON key %q", key)
+				}
+			}
+			if got := m["number"]; got != "100" {
+				t.Errorf(`ToMap()["number"] = %#v, want "100"`, got)
+			}
+			if got, want := m["hash"], h.Hash().Hex(); got != want {
+				t.Errorf(`ToMap()["hash"] = %#v, want %q`, got, want)
+			}
+			if _, ok := m["gasLimit"].(uint64); !ok {
+				t.Errorf(`ToMap()["gasLimit"] = %#v, want uint64`, m["gasLimit"])
+			}
+			if _, ok := m["extraData"].(string); !ok {
+				t.Errorf(`ToMap()["extraData"] = %#v, want string`, m["extraData"])
+			}
+		})
+	}
+	m := headers["v3"].ToMap()
+	if m["extraData"] != "0xab" || m["epoch"] != "7" || m["shardStateRoot"] != nil {
+		t.Errorf("ToMap() = %v", m)
+	}
+	if got := headers["v4"].ToMap()["baseFeePerGas"]; got != "1000000000" {
+		t.Errorf(`ToMap()["baseFeePerGas"] = %#v, want "1000000000"`, got)
+	}
+	if _, ok := headers["v3"].ToMap()["baseFeePerGas"]; ok {
+		t.Error("ToMap() includes absent base fee")
+	}
+	if (*Header)(nil).ToMap() != nil {
+		t.Error("ToMap() of nil header is not nil")
+	}
+}
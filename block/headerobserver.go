@@ -0,0 +1,32 @@
+package block
+
+import "sync/atomic"
+
+// decodeObserver holds the decodeObserverFunc set by SetDecodeObserver.
+var decodeObserver atomic.Value
+
+// decodeObserverFunc wraps the observer so that a nil one can be stored.
+type decodeObserverFunc struct {
+	observe func(version string)
+}
+
+// SetDecodeObserver sets a callback that DecodeRLP invokes with the version
+// name of each header it decodes, e.g. to count decoded header versions in a
+// metrics library while tracking a migration.  The version names are those
+// returned by Version, e.g. "legacy" or "v3".  A nil observer, the default,
+// disables the callback.
+//
+// The observer is called synchronously on the decoding goroutine, possibly
+// from several goroutines at once, so it should be fast and safe for
+// concurrent use.
+func SetDecodeObserver(observe func(version string)) {
+	decodeObserver.Store(decodeObserverFunc{observe})
+}
+
+// observeDecode reports a header decoded from the given encoding to the
+// decode observer, if any.
+func observeDecode(raw []byte) {
+	if f, _ := decodeObserver.Load().(decodeObserverFunc); f.observe != nil {
+		f.observe(encodedHeaderVersion(raw))
+	}
+}
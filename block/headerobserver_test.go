@@ -0,0 +1,46 @@
+package block
+
+import (
+	"math/big"
+	"testing"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestSetDecodeObserver(t *testing.T) {
+	var got []string
+	SetDecodeObserver(func(version string) { got = append(got, version) })
+	defer SetDecodeObserver(nil)
+
+	for _, h := range []*Header{
+		(&Header{Header: v3.NewHeader()}).With().Number(big.NewInt(1)).Header(),
+		(&Header{Header: v0.NewHeader()}).With().Number(big.NewInt(2)).Header(),
+	} {
+		b, err := h.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes() error = %v", err)
+		}
+		if _, err := HeaderFromBytes(b); err != nil {
+			t.Fatalf("HeaderFromBytes() error = %v", err)
+		}
+	}
+	if _, err := HeaderFromBytes([]byte{0xc1, 0xff}); err == nil {
+		t.Fatal("HeaderFromBytes() of corrupt header succeeded")
+	}
+	if len(got) != 2 || got[0] != "v3" || got[1] != legacyVersionName {
+		t.Errorf("observed versions = %q, want %q", got, []string{"v3", legacyVersionName})
+	}
+
+	SetDecodeObserver(nil)
+	b, err := (&Header{Header: v3.NewHeader()}).Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if _, err := HeaderFromBytes(b); err != nil {
+		t.Fatalf("HeaderFromBytes() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("observer called after removal: %q", got)
+	}
+}
@@ -0,0 +1,152 @@
+package block
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+
+	"github.com/harmony-one/harmony/crypto/hash"
+)
+
+// HeaderPair ..
+type HeaderPair struct {
+	BeaconHeader *Header `json:"beacon-chain-header"`
+	ShardHeader  *Header `json:"shard-chain-header"`
+}
+
+var (
+	// ErrIncompleteHeaderPair is returned when a header pair operation needs
+	// both headers but one or both are missing.
+	ErrIncompleteHeaderPair = errors.New("header pair is missing a header")
+)
+
+// Hash returns the Keccak256 hash of the concatenated tagged RLP encodings of
+// the beacon header and the shard header, in that order.  Swapping the two
+// headers thus yields a different hash.
+func (p *HeaderPair) Hash() (common.Hash, error) {
+	if p == nil || isNilHeader(p.BeaconHeader) || isNilHeader(p.ShardHeader) {
+		return common.Hash{}, ErrIncompleteHeaderPair
+	}
+	beacon, err := rlp.EncodeToBytes(p.BeaconHeader)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "cannot encode beacon header")
+	}
+	shard, err := rlp.EncodeToBytes(p.ShardHeader)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "cannot encode shard header")
+	}
+	return hash.Keccak256Hash(beacon, shard), nil
+}
+
+// isNilHeader returns whether h or its underlying header is nil.
+func isNilHeader(h *Header) bool {
+	return h == nil || h.Header == nil
+}
+
+// headerPairRLP is the RLP form of a header pair.  Each header is encoded
+// through HeaderRegistry, preceded by a flag telling whether it is present;
+// an absent header is encoded as an empty string.
+type headerPairRLP struct {
+	HasBeacon bool
+	Beacon    rlp.RawValue
+	HasShard  bool
+	Shard     rlp.RawValue
+}
+
+// EncodeRLP encodes the header pair, either header of which may be nil.
+func (p *HeaderPair) EncodeRLP(w io.Writer) error {
+	var (
+		enc headerPairRLP
+		err error
+	)
+	if enc.HasBeacon, enc.Beacon, err = encodePairHeader(p.BeaconHeader); err != nil {
+		return errors.Wrap(err, "cannot encode beacon header")
+	}
+	if enc.HasShard, enc.Shard, err = encodePairHeader(p.ShardHeader); err != nil {
+		return errors.Wrap(err, "cannot encode shard header")
+	}
+	return rlp.Encode(w, &enc)
+}
+
+// DecodeRLP decodes the header pair encoded by EncodeRLP.
+func (p *HeaderPair) DecodeRLP(s *rlp.Stream) error {
+	var dec headerPairRLP
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	beacon, err := decodePairHeader(dec.HasBeacon, dec.Beacon)
+	if err != nil {
+		return errors.Wrap(err, "cannot decode beacon header")
+	}
+	shard, err := decodePairHeader(dec.HasShard, dec.Shard)
+	if err != nil {
+		return errors.Wrap(err, "cannot decode shard header")
+	}
+	p.BeaconHeader, p.ShardHeader = beacon, shard
+	return nil
+}
+
+// encodePairHeader returns the presence flag and the RLP encoding of h.
+func encodePairHeader(h *Header) (bool, rlp.RawValue, error) {
+	if isNilHeader(h) {
+		return false, rlp.EmptyString, nil
+	}
+	var buf bytes.Buffer
+	if err := h.EncodeRLPWithRegistry(&buf, HeaderRegistry); err != nil {
+		return false, nil, err
+	}
+	return true, buf.Bytes(), nil
+}
+
+// decodePairHeader decodes a header encoded by encodePairHeader.
+func decodePairHeader(present bool, raw rlp.RawValue) (*Header, error) {
+	if !present {
+		return nil, nil
+	}
+	h := &Header{}
+	s := rlp.NewStream(bytes.NewReader(raw), uint64(len(raw)))
+	if err := h.DecodeRLPWithRegistry(s, HeaderRegistry); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// UnmarshalJSON decodes the header pair, using the version field of each
+// header to select its concrete type.  A missing or null header is decoded
+// as nil.
+func (p *HeaderPair) UnmarshalJSON(data []byte) error {
+	var dec struct {
+		BeaconHeader json.RawMessage `json:"beacon-chain-header"`
+		ShardHeader  json.RawMessage `json:"shard-chain-header"`
+	}
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	beacon, err := unmarshalPairHeader(dec.BeaconHeader)
+	if err != nil {
+		return errors.Wrap(err, "cannot decode beacon header")
+	}
+	shard, err := unmarshalPairHeader(dec.ShardHeader)
+	if err != nil {
+		return errors.Wrap(err, "cannot decode shard header")
+	}
+	p.BeaconHeader, p.ShardHeader = beacon, shard
+	return nil
+}
+
+// unmarshalPairHeader decodes a header from its JSON form, or returns nil
+// if there is none.
+func unmarshalPairHeader(data json.RawMessage) (*Header, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	h := &Header{}
+	if err := h.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
@@ -0,0 +1,113 @@
+package block
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	v1 "github.com/harmony-one/harmony/block/v1"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeaderPair_Hash(t *testing.T) {
+	beacon := (&Header{Header: v3.NewHeader()}).With().ShardID(0).Number(big.NewInt(10)).Header()
+	shard := (&Header{Header: v3.NewHeader()}).With().ShardID(1).Number(big.NewInt(20)).Header()
+
+	pair := &HeaderPair{BeaconHeader: beacon, ShardHeader: shard}
+	got, err := pair.Hash()
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	again, err := (&HeaderPair{BeaconHeader: beacon.Copy(), ShardHeader: shard.Copy()}).Hash()
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if got != again {
+		t.Errorf("Hash() not deterministic: %x != %x", got, again)
+	}
+	swapped, err := (&HeaderPair{BeaconHeader: shard, ShardHeader: beacon}).Hash()
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if got == swapped {
+		t.Error("Hash() is not order-sensitive")
+	}
+
+	for name, pair := range map[string]*HeaderPair{
+		"NoBeacon": {ShardHeader: shard},
+		"NoShard":  {BeaconHeader: beacon},
+		"Neither":  {},
+		"Nil":      nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := pair.Hash(); err != ErrIncompleteHeaderPair {
+				t.Errorf("Hash() error = %v, want %v", err, ErrIncompleteHeaderPair)
+			}
+		})
+	}
+}
+
+// headerPairCombinations returns header pairs for every combination of
+// present and absent headers.
+func headerPairCombinations() map[string]*HeaderPair {
+	beacon := (&Header{Header: v3.NewHeader()}).With().ShardID(0).Number(big.NewInt(10)).Header()
+	shard := (&Header{Header: v1.NewHeader()}).With().ShardID(1).Number(big.NewInt(20)).Header()
+	return map[string]*HeaderPair{
+		"Both":       {BeaconHeader: beacon, ShardHeader: shard},
+		"BeaconOnly": {BeaconHeader: beacon},
+		"ShardOnly":  {ShardHeader: shard},
+		"Neither":    {},
+	}
+}
+
+func checkHeaderPairsEqual(t *testing.T, got, want *HeaderPair) {
+	t.Helper()
+	if !got.BeaconHeader.Equal(want.BeaconHeader) {
+		t.Errorf("beacon header mismatch: got %v, want %v", got.BeaconHeader, want.BeaconHeader)
+	}
+	if !got.ShardHeader.Equal(want.ShardHeader) {
+		t.Errorf("shard header mismatch: got %v, want %v", got.ShardHeader, want.ShardHeader)
+	}
+	if got.BeaconHeader != nil && got.BeaconHeader.Version() != want.BeaconHeader.Version() {
+		t.Errorf("beacon header version = %q, want %q",
+			got.BeaconHeader.Version(), want.BeaconHeader.Version())
+	}
+	if got.ShardHeader != nil && got.ShardHeader.Version() != want.ShardHeader.Version() {
+		t.Errorf("shard header version = %q, want %q",
+			got.ShardHeader.Version(), want.ShardHeader.Version())
+	}
+}
+
+func TestHeaderPair_RLPRoundTrip(t *testing.T) {
+	for name, pair := range headerPairCombinations() {
+		t.Run(name, func(t *testing.T) {
+			b, err := rlp.EncodeToBytes(pair)
+			if err != nil {
+				t.Fatalf("EncodeRLP() error = %v", err)
+			}
+			var got HeaderPair
+			if err := rlp.DecodeBytes(b, &got); err != nil {
+				t.Fatalf("DecodeRLP() error = %v", err)
+			}
+			checkHeaderPairsEqual(t, &got, pair)
+		})
+	}
+}
+
+func TestHeaderPair_JSONRoundTrip(t *testing.T) {
+	for name, pair := range headerPairCombinations() {
+		t.Run(name, func(t *testing.T) {
+			b, err := json.Marshal(pair)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			var got HeaderPair
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			checkHeaderPairsEqual(t, &got, pair)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package block
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/pkg/errors"
+
+	"github.com/harmony-one/harmony/crypto/hash"
+)
+
+// ErrInvalidAccountProof is returned by VerifyAccountProof when the proof does
+// not prove the given account value against the header state root.
+var ErrInvalidAccountProof = errors.New("invalid account proof")
+
+// StateRootHash returns the root hash of the state trie as of this block.  It
+// is the same as Root, named for clarity at call sites that deal with proofs.
+func (h *Header) StateRootHash() common.Hash {
+	return h.Root()
+}
+
+// VerifyAccountProof verifies a Merkle-Patricia proof that the state trie
+// rooted at the header state root maps the given account address to value,
+// the RLP encoding of the account.  A nil value verifies a proof that the
+// account does not exist.
+//
+// proof is the list of RLP-encoded trie nodes on the path from the root to
+// the account, as produced by state trie Prove.  State trie keys are the
+// Keccak256 hashes of account addresses.
+//
+// The returned error wraps ErrInvalidAccountProof.
+func (h *Header) VerifyAccountProof(
+	addr common.Address, proof [][]byte, value []byte,
+) error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	proofDB := memorydb.New()
+	for _, node := range proof {
+		if err := proofDB.Put(hash.Keccak256(node), node); err != nil {
+			return errors.Wrap(err, "cannot index proof node")
+		}
+	}
+	root := h.StateRootHash()
+	proven, _, err := trie.VerifyProof(root, hash.Keccak256(addr.Bytes()), proofDB)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAccountProof,
+			"account %s, root %s: %v", addr.Hex(), root.Hex(), err)
+	}
+	if !bytes.Equal(proven, value) {
+		return errors.Wrapf(ErrInvalidAccountProof,
+			"account %s, root %s: proven value %x, want %x",
+			addr.Hex(), root.Hex(), proven, value)
+	}
+	return nil
+}
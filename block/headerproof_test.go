@@ -0,0 +1,91 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/pkg/errors"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+	"github.com/harmony-one/harmony/crypto/hash"
+)
+
+// proofList collects proof nodes written by trie Prove.
+type proofList [][]byte
+
+func (p *proofList) Put(key []byte, value []byte) error {
+	*p = append(*p, value)
+	return nil
+}
+
+func (p *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+func TestHeader_VerifyAccountProof(t *testing.T) {
+	accounts := map[common.Address][]byte{}
+	tr, err := trie.NewSecure(common.Hash{}, trie.NewDatabase(memorydb.New()))
+	if err != nil {
+		t.Fatalf("cannot create trie: %v", err)
+	}
+	for i := byte(1); i <= 16; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		accounts[addr] = append([]byte("account"), i)
+		tr.Update(addr.Bytes(), accounts[addr])
+	}
+	root, err := tr.Commit(nil)
+	if err != nil {
+		t.Fatalf("cannot commit trie: %v", err)
+	}
+	h := (&Header{Header: v3.NewHeader()}).With().Root(root).Header()
+	if got := h.StateRootHash(); got != root {
+		t.Fatalf("StateRootHash() = %s, want %s", got.Hex(), root.Hex())
+	}
+	prove := func(addr common.Address) [][]byte {
+		// SecureTrie.Prove takes the already hashed key.
+		var proof proofList
+		if err := tr.Prove(hash.Keccak256(addr.Bytes()), 0, &proof); err != nil {
+			t.Fatalf("cannot prove %s: %v", addr.Hex(), err)
+		}
+		return proof
+	}
+
+	addr := common.BytesToAddress([]byte{5})
+	proof := prove(addr)
+	if err := h.VerifyAccountProof(addr, proof, accounts[addr]); err != nil {
+		t.Errorf("VerifyAccountProof() of valid proof = %v", err)
+	}
+	missing := common.BytesToAddress([]byte{0xff})
+	if err := h.VerifyAccountProof(missing, prove(missing), nil); err != nil {
+		t.Errorf("VerifyAccountProof() of valid absence proof = %v", err)
+	}
+
+	t.Run("WrongValue", func(t *testing.T) {
+		err := h.VerifyAccountProof(addr, proof, []byte("forged"))
+		if errors.Cause(err) != ErrInvalidAccountProof {
+			t.Errorf("VerifyAccountProof() = %v, want %v", err, ErrInvalidAccountProof)
+		}
+	})
+	t.Run("TamperedNode", func(t *testing.T) {
+		tampered := make([][]byte, len(proof))
+		for i, node := range proof {
+			tampered[i] = common.CopyBytes(node)
+		}
+		last := tampered[len(tampered)-1]
+		last[len(last)-1] ^= 0xff
+		err := h.VerifyAccountProof(addr, tampered, accounts[addr])
+		if errors.Cause(err) != ErrInvalidAccountProof {
+			t.Errorf("VerifyAccountProof() = %v, want %v", err, ErrInvalidAccountProof)
+		}
+	})
+	t.Run("WrongRoot", func(t *testing.T) {
+		other := (&Header{Header: v3.NewHeader()}).With().
+			Root(common.HexToHash("0x1234")).Header()
+		err := other.VerifyAccountProof(addr, proof, accounts[addr])
+		if errors.Cause(err) != ErrInvalidAccountProof {
+			t.Errorf("VerifyAccountProof() = %v, want %v", err, ErrInvalidAccountProof)
+		}
+	})
+}
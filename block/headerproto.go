@@ -0,0 +1,145 @@
+package block
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/harmony-one/harmony/block/blockpb"
+)
+
+// ToProto returns the protobuf form of the header.  It fails if the header
+// version is not registered in HeaderRegistry.
+func (h *Header) ToProto() (*blockpb.Header, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	v, ok := headerVersionOf(h.Header)
+	if !ok {
+		return nil, errors.Errorf("unregistered header type %T", h.Header)
+	}
+	parentHash, root, txHash := h.ParentHash(), h.Root(), h.TxHash()
+	receiptHash, bloom, coinbase := h.ReceiptHash(), h.Bloom(), h.Coinbase()
+	mixDigest, lastCommitSignature := h.MixDigest(), h.LastCommitSignature()
+	pb := &blockpb.Header{
+		Version:             v.name,
+		ParentHash:          parentHash[:],
+		Coinbase:            coinbase[:],
+		Root:                root[:],
+		TxHash:              txHash[:],
+		ReceiptHash:         receiptHash[:],
+		Bloom:               bloom[:],
+		Number:              h.Number().Bytes(),
+		GasLimit:            h.GasLimit(),
+		GasUsed:             h.GasUsed(),
+		Time:                h.Time().Bytes(),
+		Extra:               h.Extra(),
+		MixDigest:           mixDigest[:],
+		ViewId:              h.ViewID().Bytes(),
+		Epoch:               h.Epoch().Bytes(),
+		ShardId:             h.ShardID(),
+		LastCommitSignature: lastCommitSignature[:],
+		LastCommitBitmap:    h.LastCommitBitmap(),
+		ShardState:          h.ShardState(),
+	}
+	if v.features.crossShardReceipts {
+		outgoingReceiptHash := h.OutgoingReceiptHash()
+		incomingReceiptHash := h.IncomingReceiptHash()
+		pb.OutgoingReceiptHash = outgoingReceiptHash[:]
+		pb.IncomingReceiptHash = incomingReceiptHash[:]
+	}
+	if v.features.shardStateRoot {
+		shardStateHash := h.ShardStateHash()
+		pb.ShardStateHash = shardStateHash[:]
+	}
+	if v.features.randomness {
+		pb.Vrf, pb.Vdf = h.Vrf(), h.Vdf()
+	}
+	if v.features.crossLinks {
+		pb.CrossLinks = h.CrossLinks()
+	}
+	if v.features.slashes {
+		pb.Slashes = h.Slashes()
+	}
+	if v.features.baseFee {
+		pb.BaseFee = h.BaseFee().Bytes()
+	}
+	return pb, nil
+}
+
+// HeaderFromProto returns the header represented by the given protobuf form.
+// The version field selects the concrete header type; fields that the version
+// does not carry are ignored.
+func HeaderFromProto(pb *blockpb.Header) (*Header, error) {
+	if pb == nil {
+		return nil, ErrHeaderIsNil
+	}
+	v, ok := headerVersionByName(pb.Version)
+	if !ok {
+		return nil, errors.Errorf("unknown header version %#v", pb.Version)
+	}
+	var err error
+	hash := func(name string, b []byte) common.Hash {
+		if err == nil && len(b) != common.HashLength {
+			err = errors.Errorf("invalid %s length %d", name, len(b))
+		}
+		return common.BytesToHash(b)
+	}
+	hif := v.factory()
+	hif.SetParentHash(hash("parentHash", pb.ParentHash))
+	hif.SetRoot(hash("root", pb.Root))
+	hif.SetTxHash(hash("txHash", pb.TxHash))
+	hif.SetReceiptHash(hash("receiptHash", pb.ReceiptHash))
+	hif.SetMixDigest(hash("mixDigest", pb.MixDigest))
+	if v.features.crossShardReceipts {
+		hif.SetOutgoingReceiptHash(hash("outgoingReceiptHash", pb.OutgoingReceiptHash))
+		hif.SetIncomingReceiptHash(hash("incomingReceiptHash", pb.IncomingReceiptHash))
+	}
+	if v.features.shardStateRoot {
+		hif.SetShardStateHash(hash("shardStateHash", pb.ShardStateHash))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(pb.Coinbase) != common.AddressLength {
+		return nil, errors.Errorf("invalid coinbase length %d", len(pb.Coinbase))
+	}
+	hif.SetCoinbase(common.BytesToAddress(pb.Coinbase))
+	if len(pb.Bloom) != types.BloomByteLength {
+		return nil, errors.Errorf("invalid bloom length %d", len(pb.Bloom))
+	}
+	hif.SetBloom(types.BytesToBloom(pb.Bloom))
+	var sig [96]byte
+	if len(pb.LastCommitSignature) != len(sig) {
+		return nil, errors.Errorf("invalid lastCommitSignature length %d",
+			len(pb.LastCommitSignature))
+	}
+	copy(sig[:], pb.LastCommitSignature)
+	hif.SetLastCommitSignature(sig)
+	hif.SetNumber(new(big.Int).SetBytes(pb.Number))
+	hif.SetGasLimit(pb.GasLimit)
+	hif.SetGasUsed(pb.GasUsed)
+	hif.SetTime(new(big.Int).SetBytes(pb.Time))
+	hif.SetExtra(pb.Extra)
+	hif.SetViewID(new(big.Int).SetBytes(pb.ViewId))
+	hif.SetEpoch(new(big.Int).SetBytes(pb.Epoch))
+	hif.SetShardID(pb.ShardId)
+	hif.SetLastCommitBitmap(pb.LastCommitBitmap)
+	hif.SetShardState(pb.ShardState)
+	if v.features.randomness {
+		hif.SetVrf(pb.Vrf)
+		hif.SetVdf(pb.Vdf)
+	}
+	if v.features.crossLinks {
+		hif.SetCrossLinks(pb.CrossLinks)
+	}
+	if v.features.slashes {
+		hif.SetSlashes(pb.Slashes)
+	}
+	if v.features.baseFee {
+		hif.SetBaseFee(new(big.Int).SetBytes(pb.BaseFee))
+	}
+	return &Header{Header: hif}, nil
+}
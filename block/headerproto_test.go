@@ -0,0 +1,105 @@
+package block
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/harmony-one/harmony/block/blockpb"
+	blockif "github.com/harmony-one/harmony/block/interface"
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v1 "github.com/harmony-one/harmony/block/v1"
+	v2 "github.com/harmony-one/harmony/block/v2"
+	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
+)
+
+func TestHeader_ProtoRoundTrip(t *testing.T) {
+	for _, hif := range []blockif.Header{
+		v0.NewHeader(), v1.NewHeader(), v2.NewHeader(), v3.NewHeader(),
+		v4.NewHeader(),
+	} {
+		t.Run(headerVersionName(hif), func(t *testing.T) {
+			bigNumber, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+			h := (&Header{Header: hif}).With().
+				ParentHash(common.HexToHash("0x1234")).
+				Coinbase(common.HexToAddress("0x5678")).
+				Number(bigNumber).
+				GasLimit(80000000).
+				GasUsed(21000).
+				Time(big.NewInt(1600000000)).
+				Extra([]byte("extra")).
+				ViewID(big.NewInt(105)).
+				Epoch(big.NewInt(7)).
+				ShardID(1).
+				LastCommitSignature([96]byte{1, 2, 3}).
+				LastCommitBitmap([]byte{0xff, 0x01}).
+				ShardState([]byte{0xc0}).
+				Header()
+			v, _ := headerVersionOf(hif)
+			if v.features.randomness {
+				h.SetVrf([]byte{4, 5})
+				h.SetVdf([]byte{6, 7})
+			}
+			if v.features.crossLinks {
+				h.SetCrossLinks([]byte{0xc1, 0x80})
+			}
+			if v.features.slashes {
+				h.SetSlashes([]byte{0xc0})
+			}
+			if v.features.baseFee {
+				h.SetBaseFee(big.NewInt(1000000000))
+			}
+			pb, err := h.ToProto()
+			if err != nil {
+				t.Fatalf("ToProto() error = %v", err)
+			}
+			b, err := proto.Marshal(pb)
+			if err != nil {
+				t.Fatalf("cannot marshal protobuf: %v", err)
+			}
+			var decoded blockpb.Header
+			if err := proto.Unmarshal(b, &decoded); err != nil {
+				t.Fatalf("cannot unmarshal protobuf: %v", err)
+			}
+			got, err := HeaderFromProto(&decoded)
+			if err != nil {
+				t.Fatalf("HeaderFromProto() error = %v", err)
+			}
+			if reflect.TypeOf(got.Header) != reflect.TypeOf(hif) {
+				t.Errorf("HeaderFromProto() got type %T, want %T", got.Header, hif)
+			}
+			if got.Hash() != h.Hash() {
+				t.Errorf("HeaderFromProto() got hash %x, want %x", got.Hash(), h.Hash())
+			}
+		})
+	}
+}
+
+func TestHeaderFromProto_Invalid(t *testing.T) {
+	valid, err := (&Header{Header: v3.NewHeader()}).ToProto()
+	if err != nil {
+		t.Fatalf("ToProto() error = %v", err)
+	}
+	tests := map[string]func(pb *blockpb.Header){
+		"UnknownVersion": func(pb *blockpb.Header) { pb.Version = "v99" },
+		"ShortHash":      func(pb *blockpb.Header) { pb.ParentHash = pb.ParentHash[1:] },
+		"ShortCoinbase":  func(pb *blockpb.Header) { pb.Coinbase = nil },
+		"ShortSignature": func(pb *blockpb.Header) { pb.LastCommitSignature = nil },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			pb := proto.Clone(valid).(*blockpb.Header)
+			mutate(pb)
+			if _, err := HeaderFromProto(pb); err == nil {
+				t.Error("HeaderFromProto() expected error")
+			}
+		})
+	}
+	if _, err := HeaderFromProto(nil); err != ErrHeaderIsNil {
+		t.Errorf("HeaderFromProto(nil) error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
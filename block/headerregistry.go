@@ -0,0 +1,238 @@
+package block
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/harmony-one/taggedrlp"
+	"github.com/pkg/errors"
+
+	blockif "github.com/harmony-one/harmony/block/interface"
+)
+
+var (
+	// ErrHeaderVersionTaken is returned by RegisterHeaderVersion when the tag
+	// is already registered.
+	ErrHeaderVersionTaken = errors.New("header version tag already registered")
+
+	// ErrHeaderRegistryInUse is returned by RegisterHeaderVersion once a
+	// header has been encoded or decoded with HeaderRegistry.
+	ErrHeaderRegistryInUse = errors.New("header registry already in use")
+)
+
+// RegisterHeaderVersion registers a custom header version, e.g. of a
+// sidechain, under the given tag in HeaderRegistry, so that EncodeRLP and
+// DecodeRLP support it.  The factory must return a new, ready-to-decode
+// instance of a pointer type implementing blockif.Header, as with
+// taggedrlp.Registry.AddFactory.  Registering a tag that is already taken,
+// including the empty legacy tag, fails with ErrHeaderVersionTaken.
+//
+// Register custom versions during initialization: once a header has been
+// encoded or decoded with HeaderRegistry, registration fails with
+// ErrHeaderRegistryInUse.  Version reports the tag of a custom version, but
+// the other version-aware helpers of this package do not know about it and
+// treat its optional fields as unsupported.
+func RegisterHeaderVersion(tag string, factory func() blockif.Header) error {
+	return defaultHeaderRegistry.register(taggedrlp.Tag(tag), factory)
+}
+
+// headerRegistry is a taggedrlp registry of header versions together with
+// this package's own record of what has been registered in it, as
+// taggedrlp.Registry offers no way to inspect its contents.
+type headerRegistry struct {
+	reg *taggedrlp.Registry
+
+	mu       sync.RWMutex
+	versions []registeredHeaderVersion // in registration order
+	tags     map[reflect.Type]taggedrlp.Tag
+	inUse    uint32 // atomic; set once reg is read
+}
+
+// registeredHeaderVersion is a header version registered in a headerRegistry.
+type registeredHeaderVersion struct {
+	tag     taggedrlp.Tag
+	typ     reflect.Type // the pointer type returned by factory
+	factory func() blockif.Header
+}
+
+// defaultHeaderRegistry is the headerRegistry behind HeaderRegistry.
+var defaultHeaderRegistry = newHeaderRegistry()
+
+// newHeaderRegistry returns a new headerRegistry populated with all header
+// versions known to this package.
+func newHeaderRegistry() *headerRegistry {
+	r := &headerRegistry{
+		reg:  taggedrlp.NewRegistry(),
+		tags: make(map[reflect.Type]taggedrlp.Tag, len(headerVersions)),
+	}
+	for _, v := range headerVersions {
+		if err := r.register(v.tag, v.factory); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// register adds a header version under the given tag.
+func (r *headerRegistry) register(tag taggedrlp.Tag, factory func() blockif.Header) error {
+	if factory == nil {
+		return errors.New("nil header factory")
+	}
+	proto := factory()
+	if proto == nil {
+		return errors.Errorf("factory for header version %q returned nil", tag)
+	}
+	if v := reflect.ValueOf(proto); v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.Errorf("factory for header version %q returned %T, not a non-nil pointer",
+			tag, proto)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range r.versions {
+		if v.tag == tag {
+			return errors.Wrapf(ErrHeaderVersionTaken, "tag %q", tag)
+		}
+	}
+	if atomic.LoadUint32(&r.inUse) != 0 {
+		return errors.Wrapf(ErrHeaderRegistryInUse, "cannot register header version %q", tag)
+	}
+	if err := r.reg.Register(tag, proto); err != nil {
+		if _, ok := err.(taggedrlp.TagAlreadyBoundToType); ok {
+			return errors.Wrapf(ErrHeaderVersionTaken, "tag %q", tag)
+		}
+		return errors.Wrapf(err, "cannot register header version %q", tag)
+	}
+	if err := r.reg.AddFactory(func() interface{} { return factory() }); err != nil {
+		return errors.Wrapf(err, "cannot register header version %q", tag)
+	}
+	typ := reflect.TypeOf(proto)
+	r.versions = append(r.versions, registeredHeaderVersion{
+		tag: tag, typ: typ, factory: factory,
+	})
+	r.tags[typ] = tag
+	return nil
+}
+
+// use marks the registry as in use, after which register refuses new
+// versions.  Call it before reading r.reg.
+func (r *headerRegistry) use() {
+	if atomic.LoadUint32(&r.inUse) == 0 {
+		// Wait for any registration in progress.
+		r.mu.Lock()
+		atomic.StoreUint32(&r.inUse, 1)
+		r.mu.Unlock()
+	}
+}
+
+// tagOf returns the tag under which the type of the given header
+// implementation is registered.
+func (r *headerRegistry) tagOf(hif blockif.Header) (taggedrlp.Tag, bool) {
+	if hif == nil {
+		return "", false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tag, ok := r.tags[reflect.TypeOf(hif)]
+	return tag, ok
+}
+
+// versionName returns the version name of the given header implementation,
+// i.e. "legacy" for the untagged v0 and its tag otherwise, or an empty string
+// if its type is not registered.
+func (r *headerRegistry) versionName(hif blockif.Header) string {
+	tag, ok := r.tagOf(hif)
+	switch {
+	case !ok:
+		return ""
+	case tag == taggedrlp.LegacyTag:
+		return legacyVersionName
+	}
+	return string(tag)
+}
+
+// versionNames returns the sorted names of the registered header versions,
+// with "legacy" standing for the untagged v0.
+func (r *headerRegistry) versionNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.versions))
+	for _, v := range r.versions {
+		name := string(v.tag)
+		if v.tag == taggedrlp.LegacyTag {
+			name = legacyVersionName
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// useHeaderRegistry marks HeaderRegistry as in use if reg is HeaderRegistry.
+func useHeaderRegistry(reg *taggedrlp.Registry) {
+	if reg == defaultHeaderRegistry.reg {
+		defaultHeaderRegistry.use()
+	}
+}
+
+// validateRegistry checks that the given header registry is consistent: every
+// registered tag maps to a distinct type with a factory, and the underlying
+// taggedrlp registry encodes and decodes each version under its recorded tag.
+// The returned error lists all inconsistencies found.
+func validateRegistry(r *headerRegistry) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var problems []string
+	tagOfType := make(map[reflect.Type]taggedrlp.Tag, len(r.versions))
+	for _, v := range r.versions {
+		if other, dup := tagOfType[v.typ]; dup {
+			problems = append(problems, fmt.Sprintf(
+				"type %v is registered under both tags %q and %q", v.typ, other, v.tag))
+		} else {
+			tagOfType[v.typ] = v.tag
+		}
+		if v.factory == nil {
+			problems = append(problems, fmt.Sprintf(
+				"type %v of tag %q has no factory", v.typ, v.tag))
+			continue
+		}
+		if msg := checkRegistryRoundTrip(r.reg, v); msg != "" {
+			problems = append(problems, msg)
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.Errorf("inconsistent header registry: %s",
+			strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkRegistryRoundTrip encodes a new instance of the given version with reg
+// and returns a description of the problem if it does not encode under the
+// version tag or decode back to the version type, or "" if it does.
+func checkRegistryRoundTrip(reg *taggedrlp.Registry, v registeredHeaderVersion) string {
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, v.factory()); err != nil {
+		return fmt.Sprintf("type %v of tag %q cannot be encoded: %v", v.typ, v.tag, err)
+	}
+	want := string(v.tag)
+	if v.tag == taggedrlp.LegacyTag {
+		want = legacyVersionName
+	}
+	if tag := encodedHeaderVersion(buf.Bytes()); tag != want {
+		return fmt.Sprintf("type %v of tag %q is encoded under tag %q", v.typ, v.tag, tag)
+	}
+	decoded, err := decodeTaggedHeader(buf.Bytes(), reg)
+	if err != nil {
+		return fmt.Sprintf("type %v of tag %q cannot be decoded: %v", v.typ, v.tag, err)
+	}
+	if typ := reflect.TypeOf(decoded); typ != v.typ {
+		return fmt.Sprintf("type %v of tag %q is decoded as %v", v.typ, v.tag, typ)
+	}
+	return ""
+}
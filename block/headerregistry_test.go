@@ -0,0 +1,164 @@
+package block
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+
+	blockif "github.com/harmony-one/harmony/block/interface"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestValidateRegistry(t *testing.T) {
+	if err := validateRegistry(defaultHeaderRegistry); err != nil {
+		t.Errorf("validateRegistry(defaultHeaderRegistry) = %v", err)
+	}
+	if err := validateRegistry(newHeaderRegistry()); err != nil {
+		t.Errorf("validateRegistry(newHeaderRegistry()) = %v", err)
+	}
+}
+
+func TestValidateRegistry_DuplicateType(t *testing.T) {
+	r := newHeaderRegistry()
+	// register itself refuses the duplicate...
+	if err := r.register("v99", func() blockif.Header { return v3.NewHeader() }); err == nil {
+		t.Fatal("register() of a duplicate type succeeded")
+	}
+	// ...so corrupt the record directly.
+	dup := r.versions[len(r.versions)-1]
+	dup.tag = "v99"
+	r.versions = append(r.versions, dup)
+	err := validateRegistry(r)
+	if err == nil || !strings.Contains(err.Error(), `registered under both tags`) {
+		t.Errorf("validateRegistry() = %v, want duplicate type error", err)
+	}
+}
+
+func TestValidateRegistry_MissingFactory(t *testing.T) {
+	r := newHeaderRegistry()
+	r.versions = append(r.versions, registeredHeaderVersion{
+		tag: "v99", typ: reflect.TypeOf(&customHeader{}),
+	})
+	err := validateRegistry(r)
+	if err == nil || !strings.Contains(err.Error(), `of tag "v99" has no factory`) {
+		t.Errorf("validateRegistry() = %v, want missing factory error", err)
+	}
+}
+
+func TestValidateRegistry_Unregistered(t *testing.T) {
+	r := newHeaderRegistry()
+	r.versions = append(r.versions, registeredHeaderVersion{
+		tag:     "v99",
+		typ:     reflect.TypeOf(&customHeader{}),
+		factory: func() blockif.Header { return &customHeader{v3.NewHeader()} },
+	})
+	err := validateRegistry(r)
+	if err == nil || !strings.Contains(err.Error(), `of tag "v99" cannot be encoded`) {
+		t.Errorf("validateRegistry() = %v, want encoding error", err)
+	}
+}
+
+// customHeader is a mock sidechain header version.
+type customHeader struct {
+	*v3.Header
+}
+
+// customHeader2 is another mock sidechain header version.
+type customHeader2 struct {
+	*v3.Header
+}
+
+func TestRegisterHeaderVersion(t *testing.T) {
+	r := newHeaderRegistry()
+	reg := r.reg
+	factory := func() blockif.Header { return &customHeader{v3.NewHeader()} }
+	if err := r.register("side1", factory); err != nil {
+		t.Fatalf("register() error = %v", err)
+	}
+	if err := validateRegistry(r); err != nil {
+		t.Errorf("validateRegistry() after registration = %v", err)
+	}
+	for _, test := range []struct {
+		hif  blockif.Header
+		want string
+	}{
+		{factory(), "side1"},
+		{v3.NewHeader(), "v3"},
+		{&customHeader2{v3.NewHeader()}, ""},
+	} {
+		if got := r.versionName(test.hif); got != test.want {
+			t.Errorf("versionName(%T) = %q, want %q", test.hif, got, test.want)
+		}
+	}
+	h := (&Header{Header: factory()}).With().
+		Number(big.NewInt(100)).ShardID(7).Extra([]byte("side")).Header()
+	var buf bytes.Buffer
+	if err := h.EncodeRLPWithRegistry(&buf, reg); err != nil {
+		t.Fatalf("EncodeRLPWithRegistry() error = %v", err)
+	}
+	var got Header
+	if err := got.DecodeRLPWithRegistry(rlp.NewStream(&buf, 0), reg); err != nil {
+		t.Fatalf("DecodeRLPWithRegistry() error = %v", err)
+	}
+	if _, ok := got.Header.(*customHeader); !ok {
+		t.Errorf("decoded header type = %T, want *customHeader", got.Header)
+	}
+	if got.Number().Cmp(big.NewInt(100)) != 0 || got.ShardID() != 7 ||
+		string(got.Extra()) != "side" {
+		t.Errorf("decoded header = %v, want %v", &got, h)
+	}
+
+	if err := r.register("side1", factory); errors.Cause(err) != ErrHeaderVersionTaken {
+		t.Errorf("register() of taken tag error = %v, want %v", err, ErrHeaderVersionTaken)
+	}
+}
+
+func TestRegisterHeaderVersion_InUse(t *testing.T) {
+	r := newHeaderRegistry()
+	if err := r.register("side1", func() blockif.Header { return &customHeader{v3.NewHeader()} }); err != nil {
+		t.Fatalf("register() before use error = %v", err)
+	}
+	r.use()
+	err := r.register("side2", func() blockif.Header { return &customHeader2{v3.NewHeader()} })
+	if errors.Cause(err) != ErrHeaderRegistryInUse {
+		t.Errorf("register() after use error = %v, want %v", err, ErrHeaderRegistryInUse)
+	}
+	if got, want := r.versionNames(), []string{"legacy", "side1", "v1", "v2", "v3", "v4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("versionNames() = %v, want %v", got, want)
+	}
+
+	// Encoding with HeaderRegistry puts it in use.
+	h := &Header{Header: v3.NewHeader()}
+	if _, err := h.Bytes(); err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	err = RegisterHeaderVersion("side3", func() blockif.Header { return &customHeader2{v3.NewHeader()} })
+	if errors.Cause(err) != ErrHeaderRegistryInUse {
+		t.Errorf("RegisterHeaderVersion() after use error = %v, want %v", err, ErrHeaderRegistryInUse)
+	}
+}
+
+func TestRegisterHeaderVersion_Invalid(t *testing.T) {
+	for _, tag := range []string{"", "v3"} {
+		err := RegisterHeaderVersion(tag, func() blockif.Header { return &customHeader{v3.NewHeader()} })
+		if errors.Cause(err) != ErrHeaderVersionTaken {
+			t.Errorf("RegisterHeaderVersion(%q) error = %v, want %v", tag, err, ErrHeaderVersionTaken)
+		}
+	}
+	r := newHeaderRegistry()
+	for name, factory := range map[string]func() blockif.Header{
+		"NilFactory": nil,
+		"NilHeader":  func() blockif.Header { return nil },
+		"NilPointer": func() blockif.Header { return (*customHeader)(nil) },
+		"NonPointer": func() blockif.Header { return unregisteredHeader{v3.NewHeader()} },
+	} {
+		if err := r.register("side2", factory); err == nil {
+			t.Errorf("%s: register() succeeded", name)
+		}
+	}
+}
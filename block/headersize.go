@@ -0,0 +1,32 @@
+package block
+
+import "github.com/pkg/errors"
+
+// HeaderSizeStats returns the smallest, largest, and mean (rounded down)
+// size of the tagged RLP encodings of the given headers, as returned by
+// EncodedSize, for capacity planning.  Headers may be of different versions.  An
+// empty slice yields all zeros.  A header that cannot be encoded aborts with
+// an error naming its index.
+func HeaderSizeStats(headers []*Header) (min, max, mean int, err error) {
+	total := 0
+	for i, h := range headers {
+		if isNilHeader(h) {
+			return 0, 0, 0, errors.Wrapf(ErrHeaderIsNil, "header #%d", i)
+		}
+		size, err := h.EncodedSize()
+		if err != nil {
+			return 0, 0, 0, errors.Wrapf(err, "cannot encode header #%d", i)
+		}
+		if i == 0 || size < min {
+			min = size
+		}
+		if size > max {
+			max = size
+		}
+		total += size
+	}
+	if len(headers) > 0 {
+		mean = total / len(headers)
+	}
+	return min, max, mean, nil
+}
@@ -0,0 +1,59 @@
+package block
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
+)
+
+func TestHeaderSizeStats(t *testing.T) {
+	headers := []*Header{
+		(&Header{Header: v0.NewHeader()}).With().Number(big.NewInt(1)).Header(),
+		(&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(2)).ShardState(make([]byte, 300)).Header(),
+		(&Header{Header: v4.NewHeader()}).With().
+			Number(big.NewInt(3)).Extra([]byte("extra")).Header(),
+	}
+	wantMin, wantMax, total := 0, 0, 0
+	for i, h := range headers {
+		b, err := h.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes() error = %v", err)
+		}
+		if i == 0 || len(b) < wantMin {
+			wantMin = len(b)
+		}
+		if len(b) > wantMax {
+			wantMax = len(b)
+		}
+		total += len(b)
+	}
+	wantMean := total / len(headers)
+
+	min, max, mean, err := HeaderSizeStats(headers)
+	if err != nil {
+		t.Fatalf("HeaderSizeStats() error = %v", err)
+	}
+	if min != wantMin || max != wantMax || mean != wantMean {
+		t.Errorf("HeaderSizeStats() = %d, %d, %d; want %d, %d, %d",
+			min, max, mean, wantMin, wantMax, wantMean)
+	}
+	if min == max {
+		t.Errorf("HeaderSizeStats() min = max = %d, want distinct sizes", min)
+	}
+
+	if min, max, mean, err := HeaderSizeStats(nil); min != 0 || max != 0 || mean != 0 || err != nil {
+		t.Errorf("HeaderSizeStats(nil) = %d, %d, %d, %v; want zeros", min, max, mean, err)
+	}
+
+	_, _, _, err = HeaderSizeStats(append(headers, nil))
+	if errors.Cause(err) != ErrHeaderIsNil || !strings.Contains(err.Error(), "#3") {
+		t.Errorf("HeaderSizeStats() error = %v, want %v for header #3", err, ErrHeaderIsNil)
+	}
+}
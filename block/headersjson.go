@@ -0,0 +1,34 @@
+package block
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// EncodeHeadersJSON writes the given headers to w as a JSON array, encoding
+// one header at a time so that only a single marshaled header is held in
+// memory.  An empty slice yields an empty array.  The output decodes into a
+// []*Header with encoding/json.
+func EncodeHeadersJSON(w io.Writer, headers []*Header) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for i, h := range headers {
+		if isNilHeader(h) {
+			return errors.Wrapf(ErrHeaderIsNil, "header #%d", i)
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := enc.Encode(h); err != nil {
+			return errors.Wrapf(err, "cannot encode header #%d", i)
+		}
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
@@ -0,0 +1,69 @@
+package block
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestEncodeHeadersJSON(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	headers := make([]*Header, 10)
+	for i := range headers {
+		headers[i] = randomHeader(rnd)
+	}
+	var buf bytes.Buffer
+	if err := EncodeHeadersJSON(&buf, headers); err != nil {
+		t.Fatalf("EncodeHeadersJSON() error = %v", err)
+	}
+	var got []*Header
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("cannot decode streamed output: %v", err)
+	}
+	if len(got) != len(headers) {
+		t.Fatalf("decoded %d headers, want %d", len(got), len(headers))
+	}
+	for i := range headers {
+		if got[i].Hash() != headers[i].Hash() {
+			t.Errorf("header #%d: hash %x, want %x", i, got[i].Hash(), headers[i].Hash())
+		}
+	}
+}
+
+func TestEncodeHeadersJSON_Empty(t *testing.T) {
+	for _, headers := range [][]*Header{nil, {}} {
+		var buf bytes.Buffer
+		if err := EncodeHeadersJSON(&buf, headers); err != nil {
+			t.Fatalf("EncodeHeadersJSON() error = %v", err)
+		}
+		var got []*Header
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil || got == nil || len(got) != 0 {
+			t.Errorf("EncodeHeadersJSON() wrote %q, want an empty array", buf.String())
+		}
+	}
+}
+
+func TestEncodeHeadersJSON_Nil(t *testing.T) {
+	headers := []*Header{randomHeader(rand.New(rand.NewSource(1))), nil}
+	if err := EncodeHeadersJSON(ioutil.Discard, headers); errors.Cause(err) != ErrHeaderIsNil {
+		t.Errorf("EncodeHeadersJSON() error = %v, want %v", err, ErrHeaderIsNil)
+	}
+}
+
+func BenchmarkEncodeHeadersJSON(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	headers := make([]*Header, 1000)
+	for i := range headers {
+		headers[i] = randomHeader(rnd)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := EncodeHeadersJSON(ioutil.Discard, headers); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
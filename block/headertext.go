@@ -0,0 +1,98 @@
+package block
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// headerTextHashPrefixLength is the number of leading hash bytes in the text
+// form of a header.
+const headerTextHashPrefixLength = 4
+
+// HeaderTextID is the parsed text form of a header, as returned by
+// Header.MarshalText.  It identifies a header for log correlation and lookup,
+// but only carries a prefix of its hash.
+type HeaderTextID struct {
+	ShardID    uint32
+	Number     *big.Int
+	HashPrefix [headerTextHashPrefixLength]byte
+}
+
+// MarshalText returns a compact identifier of the header for logging, of the
+// form "<shard>:<number>:<hashPrefix>", where hashPrefix is the first four
+// bytes of the header hash in hex, e.g. "1:12345:0a1b2c3d".
+//
+// The text form is lossy by design: it identifies a header for correlation
+// and lookup, but cannot reconstruct it.  See UnmarshalText.
+func (h *Header) MarshalText() ([]byte, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	hash := h.Hash()
+	return []byte(fmt.Sprintf("%d:%s:%x",
+		h.ShardID(), h.Number(), hash[:headerTextHashPrefixLength])), nil
+}
+
+// UnmarshalText parses the text form returned by MarshalText into a partial
+// header with only the shard ID and block number set; all other fields are
+// zero, so its hash differs from that of the original header.  The header
+// keeps its version, or becomes a v3 header if it has none.  Use
+// ParseHeaderTextID to also obtain the hash prefix.
+func (h *Header) UnmarshalText(text []byte) error {
+	if h == nil {
+		return ErrHeaderIsNil
+	}
+	id, err := ParseHeaderTextID(text)
+	if err != nil {
+		return err
+	}
+	v, ok := headerVersionOf(h.Header)
+	if !ok {
+		v, _ = headerVersionByName("v3")
+	}
+	h.Header = v.factory()
+	h.SetShardID(id.ShardID)
+	h.SetNumber(id.Number)
+	return nil
+}
+
+// ParseHeaderTextID parses the text form returned by Header.MarshalText.
+func ParseHeaderTextID(text []byte) (HeaderTextID, error) {
+	var id HeaderTextID
+	parts := strings.Split(string(text), ":")
+	if len(parts) != 3 {
+		return id, errors.Errorf("header text %q is not of the form shard:number:hash", text)
+	}
+	shardID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return id, errors.Wrapf(err, "invalid shard ID in header text %q", text)
+	}
+	number, ok := new(big.Int).SetString(parts[1], 10)
+	if !ok || number.Sign() < 0 {
+		return id, errors.Errorf("invalid block number in header text %q", text)
+	}
+	prefix, err := hex.DecodeString(parts[2])
+	if err != nil || len(prefix) != headerTextHashPrefixLength {
+		return id, errors.Errorf("invalid hash prefix in header text %q", text)
+	}
+	id.ShardID = uint32(shardID)
+	id.Number = number
+	copy(id.HashPrefix[:], prefix)
+	return id, nil
+}
+
+// Matches returns whether the given header has this ID.
+func (id HeaderTextID) Matches(h *Header) bool {
+	if isNilHeader(h) || id.Number == nil {
+		return false
+	}
+	hash := h.Hash()
+	return h.ShardID() == id.ShardID && h.Number().Cmp(id.Number) == 0 &&
+		bytes.Equal(hash[:headerTextHashPrefixLength], id.HashPrefix[:])
+}
@@ -0,0 +1,63 @@
+package block
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestHeader_MarshalText(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		ShardID(1).Number(big.NewInt(12345)).Extra([]byte("extra")).Header()
+	text, err := h.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error = %v", err)
+	}
+	hash := h.Hash()
+	if want := fmt.Sprintf("1:12345:%x", hash[:4]); string(text) != want {
+		t.Errorf("MarshalText() = %q, want %q", text, want)
+	}
+
+	id, err := ParseHeaderTextID(text)
+	if err != nil {
+		t.Fatalf("ParseHeaderTextID() error = %v", err)
+	}
+	if !id.Matches(h) {
+		t.Errorf("ParseHeaderTextID() = %+v does not match the header", id)
+	}
+	other := (&Header{Header: v3.NewHeader()}).With().
+		ShardID(1).Number(big.NewInt(12345)).Header()
+	if id.Matches(other) {
+		t.Error("ID matches a header with a different hash")
+	}
+
+	// The round trip keeps only the shard ID and number.
+	got := &Header{Header: v0.NewHeader()}
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText() error = %v", err)
+	}
+	if got.Version() != "legacy" || got.ShardID() != 1 || got.Number().Cmp(big.NewInt(12345)) != 0 {
+		t.Errorf("UnmarshalText() = %v", got)
+	}
+	if len(got.Extra()) != 0 || got.Hash() == h.Hash() {
+		t.Error("UnmarshalText() recovered more than the shard ID and number")
+	}
+	var fresh Header
+	if err := fresh.UnmarshalText(text); err != nil || fresh.Version() != "v3" {
+		t.Errorf("UnmarshalText() into empty header = %v, version %q", err, fresh.Version())
+	}
+}
+
+func TestParseHeaderTextID_Invalid(t *testing.T) {
+	for _, text := range []string{
+		"", "1:2", "1:2:3:4", "x:2:0a1b2c3d", "1:-2:0a1b2c3d", "1:2:0a1b", "1:2:zzzzzzzz",
+		"4294967296:2:0a1b2c3d",
+	} {
+		if _, err := ParseHeaderTextID([]byte(text)); err == nil {
+			t.Errorf("ParseHeaderTextID(%q) succeeded", text)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package block
+
+import (
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	blockif "github.com/harmony-one/harmony/block/interface"
+)
+
+// optionalHeaderField describes a group of header fields that only some
+// header versions carry.
+type optionalHeaderField struct {
+	name string
+	// carried tells whether a header version carries the field.
+	carried func(f headerFeatures) bool
+	// isSet tells whether the field holds a non-zero value.
+	isSet func(h blockif.Header) bool
+	// copy copies the field from src to dst.
+	copy func(dst, src blockif.Header)
+}
+
+var optionalHeaderFields = []optionalHeaderField{
+	{
+		"OutgoingReceiptHash/IncomingReceiptHash",
+		func(f headerFeatures) bool { return f.crossShardReceipts },
+		func(h blockif.Header) bool {
+			return h.OutgoingReceiptHash() != ethtypes.EmptyRootHash ||
+				h.IncomingReceiptHash() != ethtypes.EmptyRootHash
+		},
+		func(dst, src blockif.Header) {
+			dst.SetOutgoingReceiptHash(src.OutgoingReceiptHash())
+			dst.SetIncomingReceiptHash(src.IncomingReceiptHash())
+		},
+	},
+	{
+		"ShardStateHash",
+		func(f headerFeatures) bool { return f.shardStateRoot },
+		func(h blockif.Header) bool { return h.ShardStateHash() != (common.Hash{}) },
+		func(dst, src blockif.Header) { dst.SetShardStateHash(src.ShardStateHash()) },
+	},
+	{
+		"Vrf/Vdf",
+		func(f headerFeatures) bool { return f.randomness },
+		func(h blockif.Header) bool { return len(h.Vrf()) > 0 || len(h.Vdf()) > 0 },
+		func(dst, src blockif.Header) { dst.SetVrf(src.Vrf()); dst.SetVdf(src.Vdf()) },
+	},
+	{
+		"CrossLinks",
+		func(f headerFeatures) bool { return f.crossLinks },
+		func(h blockif.Header) bool { return len(h.CrossLinks()) > 0 },
+		func(dst, src blockif.Header) { dst.SetCrossLinks(src.CrossLinks()) },
+	},
+	{
+		"Slashes",
+		func(f headerFeatures) bool { return f.slashes },
+		func(h blockif.Header) bool { return len(h.Slashes()) > 0 },
+		func(dst, src blockif.Header) { dst.SetSlashes(src.Slashes()) },
+	},
+	{
+		"BaseFee",
+		func(f headerFeatures) bool { return f.baseFee },
+		func(h blockif.Header) bool { return h.BaseFee().Sign() != 0 },
+		func(dst, src blockif.Header) { dst.SetBaseFee(src.BaseFee()) },
+	},
+}
+
+// headerVersionIndex returns the index of the named version in
+// headerVersions, or -1 if it is not registered.
+func headerVersionIndex(name string) int {
+	for i, v := range headerVersions {
+		if v.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Upgrade returns a copy of the header converted to the given newer (or the
+// same) header version, e.g. for uniform processing of headers from before a
+// network upgrade.
+//
+// All fields that both versions carry are copied.  Fields that only the
+// target version carries, e.g. the v4 base fee, are left at their zero
+// values; note that they are thus not meaningful in the upgraded header.
+// Upgrade fails if the target version is not registered, is older than the
+// header version, or does not carry a field that is set in the header, as
+// the conversion would then lose data.
+func (h *Header) Upgrade(targetVersion string) (*Header, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	src, ok := headerVersionOf(h.Header)
+	if !ok {
+		return nil, errors.Errorf("unregistered header type %T", h.Header)
+	}
+	dst, ok := headerVersionByName(targetVersion)
+	if !ok {
+		return nil, errors.Errorf("unknown header version %#v", targetVersion)
+	}
+	if headerVersionIndex(dst.name) < headerVersionIndex(src.name) {
+		return nil, errors.Errorf("cannot downgrade header from version %s to %s",
+			src.name, dst.name)
+	}
+	return convertHeader(h, src, dst)
+}
+
+// EncodeAsVersion writes the tagged RLP encoding of the header converted to
+// the given header version, older or newer, to w.  It is a compatibility
+// testing tool for exercising decoders against each wire format; the encoded
+// header generally does not hash to the same value as the original one, so
+// do not use it on the consensus path.
+//
+// The conversion is the same as that of Upgrade.  It fails if the target
+// version is not registered or does not carry a field that is set in the
+// header.
+func (h *Header) EncodeAsVersion(w io.Writer, version string) error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	src, ok := headerVersionOf(h.Header)
+	if !ok {
+		return errors.Errorf("unregistered header type %T", h.Header)
+	}
+	dst, ok := headerVersionByName(version)
+	if !ok {
+		return errors.Errorf("unknown header version %#v", version)
+	}
+	converted, err := convertHeader(h, src, dst)
+	if err != nil {
+		return err
+	}
+	return converted.EncodeRLP(w)
+}
+
+// convertHeader returns a copy of the header, which is of version src,
+// converted to version dst.  It fails if dst does not carry a field that is
+// set in the header.
+func convertHeader(h *Header, src, dst headerVersion) (*Header, error) {
+	for _, field := range optionalHeaderFields {
+		if field.carried(src.features) && !field.carried(dst.features) &&
+			field.isSet(h.Header) {
+			return nil, errors.Errorf("header version %s does not carry %s set in version %s header",
+				dst.name, field.name, src.name)
+		}
+	}
+	hif := dst.factory()
+	hif.SetParentHash(h.ParentHash())
+	hif.SetCoinbase(h.Coinbase())
+	hif.SetRoot(h.Root())
+	hif.SetTxHash(h.TxHash())
+	hif.SetReceiptHash(h.ReceiptHash())
+	hif.SetBloom(h.Bloom())
+	hif.SetNumber(h.Number())
+	hif.SetGasLimit(h.GasLimit())
+	hif.SetGasUsed(h.GasUsed())
+	hif.SetTime(h.Time())
+	hif.SetExtra(h.Extra())
+	hif.SetMixDigest(h.MixDigest())
+	hif.SetViewID(h.ViewID())
+	hif.SetEpoch(h.Epoch())
+	hif.SetShardID(h.ShardID())
+	hif.SetLastCommitSignature(h.LastCommitSignature())
+	hif.SetLastCommitBitmap(h.LastCommitBitmap())
+	hif.SetShardState(h.ShardState())
+	for _, field := range optionalHeaderFields {
+		if field.carried(src.features) && field.carried(dst.features) {
+			field.copy(hif, h.Header)
+		}
+	}
+	return &Header{Header: hif}, nil
+}
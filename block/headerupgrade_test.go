@@ -0,0 +1,93 @@
+package block
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	v1 "github.com/harmony-one/harmony/block/v1"
+	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
+)
+
+func TestHeader_Upgrade(t *testing.T) {
+	h := (&Header{Header: v1.NewHeader()}).With().
+		ParentHash(common.HexToHash("0x1234")).
+		Number(big.NewInt(100)).
+		Epoch(big.NewInt(7)).
+		ShardID(1).
+		Extra([]byte("extra")).
+		OutgoingReceiptHash(common.HexToHash("0x5678")).
+		Vrf([]byte{1, 2}).
+		Header()
+	got, err := h.Upgrade("v3")
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if _, ok := got.Header.(*v3.Header); !ok {
+		t.Fatalf("Upgrade() got type %T, want *v3.Header", got.Header)
+	}
+	if diff := h.DiffFields(got); len(diff) != 1 || diff["Version"] != [2]string{"v1", "v3"} {
+		t.Errorf("Upgrade() changed fields: %v", diff)
+	}
+
+	got, err = got.Upgrade("v4")
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if _, ok := got.Header.(*v4.Header); !ok || got.BaseFee().Sign() != 0 {
+		t.Errorf("Upgrade() got %T with base fee %v, want *v4.Header with 0",
+			got.Header, got.BaseFee())
+	}
+}
+
+func TestHeader_Upgrade_Errors(t *testing.T) {
+	v3Header := &Header{Header: v3.NewHeader()}
+	if _, err := v3Header.Upgrade("v1"); err == nil {
+		t.Error("Upgrade() from v3 to v1 expected error")
+	}
+	if _, err := v3Header.Upgrade("v99"); err == nil {
+		t.Error("Upgrade() to unknown version expected error")
+	}
+	v1Header := (&Header{Header: v1.NewHeader()}).With().
+		ShardStateHash(common.HexToHash("0x1234")).Header()
+	if _, err := v1Header.Upgrade("v3"); err == nil {
+		t.Error("Upgrade() dropping ShardStateHash expected error")
+	}
+}
+
+func TestHeader_EncodeAsVersion(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		ParentHash(common.HexToHash("0x1234")).
+		Number(big.NewInt(100)).
+		Epoch(big.NewInt(7)).
+		ShardID(1).
+		Extra([]byte("extra")).
+		Vrf([]byte("vrf")).
+		Header()
+	var buf bytes.Buffer
+	if err := h.EncodeAsVersion(&buf, "v1"); err != nil {
+		t.Fatalf("EncodeAsVersion() error = %v", err)
+	}
+	got, err := HeaderFromBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("HeaderFromBytes() error = %v", err)
+	}
+	if got.Version() != "v1" {
+		t.Errorf("decoded header version = %s, want v1", got.Version())
+	}
+	if diff := got.DiffFields(h); len(diff) != 1 || diff["Version"] == [2]string{} {
+		t.Errorf("decoded header differs in more than version: %v", diff)
+	}
+
+	withCrossLinks := (&Header{Header: v3.NewHeader()}).With().
+		CrossLinks([]byte{0xc0}).Header()
+	if err := withCrossLinks.EncodeAsVersion(&buf, "v1"); err == nil {
+		t.Error("EncodeAsVersion() dropping CrossLinks expected error")
+	}
+	if err := h.EncodeAsVersion(&buf, "v99"); err == nil {
+		t.Error("EncodeAsVersion() to unknown version expected error")
+	}
+}
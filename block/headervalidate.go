@@ -0,0 +1,373 @@
+package block
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// Errors returned by ValidateAgainstParent, each naming the parent-child
+// invariant that the header violates.
+var (
+	// ErrParentHashMismatch ..
+	ErrParentHashMismatch = errors.New("parent hash does not match parent header hash")
+	// ErrNumberNotSequential ..
+	ErrNumberNotSequential = errors.New("block number is not parent number plus one")
+	// ErrShardIDMismatch ..
+	ErrShardIDMismatch = errors.New("shard ID differs from parent shard ID")
+	// ErrEpochDecreased ..
+	ErrEpochDecreased = errors.New("epoch is lower than parent epoch")
+	// ErrTimeNotIncreasing ..
+	ErrTimeNotIncreasing = errors.New("timestamp is not later than parent timestamp")
+	// ErrViewIDDecreased is returned by ValidateViewID.
+	ErrViewIDDecreased = errors.New("view ID is lower than parent view ID")
+	// ErrFieldUnset is returned by validators that need a header field that
+	// is not set.
+	ErrFieldUnset = errors.New("header field is not set")
+
+	// ErrGasUsedExceedsLimit ..
+	ErrGasUsedExceedsLimit = errors.New("gas used exceeds gas limit")
+	// ErrZeroGasLimit ..
+	ErrZeroGasLimit = errors.New("gas limit is zero")
+	// ErrGasLimitDeltaTooLarge is returned by ValidateGasLimitDelta when the
+	// gas limit moves too far from that of the parent.
+	ErrGasLimitDeltaTooLarge = errors.New("gas limit changed too much from parent")
+
+	// ErrHashMismatch is returned by VerifyHash when the recomputed header
+	// hash differs from the expected one.
+	ErrHashMismatch = errors.New("header hash mismatch")
+
+	// ErrRootMismatch is returned by CompareRoots when a declared trie root
+	// differs from the computed one.
+	ErrRootMismatch = errors.New("root hash mismatch")
+
+	// ErrShardIDOutOfRange is returned by ValidateShardID when the header
+	// belongs to a shard beyond the network's shard count.
+	ErrShardIDOutOfRange = errors.New("shard ID out of range")
+
+	// ErrTimestampTooFarAhead is returned by ValidateTimestamp when the
+	// header timestamp is beyond the allowed clock drift.
+	ErrTimestampTooFarAhead = errors.New("timestamp too far in the future")
+	// ErrZeroTimestamp is returned by ValidateTimestamp when a non-genesis
+	// header has a zero timestamp.
+	ErrZeroTimestamp = errors.New("timestamp is zero")
+)
+
+// ValidateAgainstParent checks that the header structurally chains to the
+// given parent header: it must refer to the parent by hash, be numbered one
+// above it, belong to the same shard, and have a non-decreasing epoch and a
+// strictly increasing timestamp.
+//
+// The returned error wraps one of the Err* variables above; use errors.Cause
+// to tell them apart.
+func (h *Header) ValidateAgainstParent(parent *Header) error {
+	if isNilHeader(h) || isNilHeader(parent) {
+		return ErrHeaderIsNil
+	}
+	if parentHash, want := h.ParentHash(), parent.Hash(); parentHash != want {
+		return invalidField("ParentHash", errors.Wrapf(ErrParentHashMismatch,
+			"parentHash=%s, parent.Hash()=%s", parentHash.Hex(), want.Hex()))
+	}
+	number, parentNumber := h.Number(), parent.Number()
+	if number == nil || parentNumber == nil ||
+		new(big.Int).Sub(number, parentNumber).Cmp(big.NewInt(1)) != 0 {
+		return invalidField("Number", errors.Wrapf(ErrNumberNotSequential,
+			"number=%v, parent.Number()=%v", number, parentNumber))
+	}
+	if shardID, parentShardID := h.ShardID(), parent.ShardID(); shardID != parentShardID {
+		return invalidField("ShardID", errors.Wrapf(ErrShardIDMismatch,
+			"shardID=%d, parent.ShardID()=%d", shardID, parentShardID))
+	}
+	epoch, parentEpoch := h.Epoch(), parent.Epoch()
+	if epoch == nil || parentEpoch == nil || epoch.Cmp(parentEpoch) < 0 {
+		return invalidField("Epoch", errors.Wrapf(ErrEpochDecreased,
+			"epoch=%v, parent.Epoch()=%v", epoch, parentEpoch))
+	}
+	timestamp, parentTimestamp := h.Time(), parent.Time()
+	if timestamp == nil || parentTimestamp == nil || timestamp.Cmp(parentTimestamp) <= 0 {
+		return invalidField("Time", errors.Wrapf(ErrTimeNotIncreasing,
+			"time=%v, parent.Time()=%v", timestamp, parentTimestamp))
+	}
+	return nil
+}
+
+// ValidateViewID checks that the view ID of the header is not lower than that
+// of the given parent.  The check only applies within an epoch between headers
+// of the same version that carry view IDs; otherwise it passes, as view IDs of
+// different epochs or versions are not comparable.
+//
+// The returned error wraps ErrViewIDDecreased, or ErrFieldUnset if either
+// header lacks an epoch or view ID.
+func (h *Header) ValidateViewID(parent *Header) error {
+	if isNilHeader(h) || isNilHeader(parent) {
+		return ErrHeaderIsNil
+	}
+	v, ok := headerVersionOf(h.Header)
+	if parentV, parentOK := headerVersionOf(parent.Header); !ok || !parentOK ||
+		!v.features.viewID || v.name != parentV.name {
+		return nil
+	}
+	epoch, parentEpoch := bigOrNil(h.Epoch), bigOrNil(parent.Epoch)
+	if epoch == nil || parentEpoch == nil {
+		return invalidField("Epoch", errors.Wrapf(ErrFieldUnset,
+			"epoch=%v, parent.Epoch()=%v", epoch, parentEpoch))
+	}
+	if epoch.Cmp(parentEpoch) != 0 {
+		return nil
+	}
+	viewID, parentViewID := bigOrNil(h.ViewID), bigOrNil(parent.ViewID)
+	if viewID == nil || parentViewID == nil {
+		return invalidField("ViewID", errors.Wrapf(ErrFieldUnset,
+			"viewID=%v, parent.ViewID()=%v", viewID, parentViewID))
+	}
+	if viewID.Cmp(parentViewID) < 0 {
+		return invalidField("ViewID", errors.Wrapf(ErrViewIDDecreased,
+			"viewID=%v, parent.ViewID()=%v", viewID, parentViewID))
+	}
+	return nil
+}
+
+// ValidateHeaderChain checks that the given headers form a contiguous chain
+// segment, validating each header against its predecessor in the slice as by
+// ValidateAgainstParent.  The first failure is returned, annotated with the
+// index of the offending header; its cause is one of the errors returned by
+// ValidateAgainstParent.  An empty or single-header segment is valid.
+func ValidateHeaderChain(headers []*Header) error {
+	for i := 1; i < len(headers); i++ {
+		if err := headers[i].ValidateAgainstParent(headers[i-1]); err != nil {
+			return errors.Wrapf(err, "header #%d", i)
+		}
+	}
+	return nil
+}
+
+// VerifyHash recomputes the header hash, bypassing the cache, and checks that
+// it equals the expected hash, e.g. the one under which the header was stored.
+// The returned error wraps ErrHashMismatch and names both hashes.
+func (h *Header) VerifyHash(expected common.Hash) error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	if actual := h.HashNoCache(); actual != expected {
+		return invalidField("Hash", errors.Wrapf(ErrHashMismatch,
+			"expected %s, computed %s", expected.Hex(), actual.Hex()))
+	}
+	return nil
+}
+
+// ValidateGas checks the gas invariants of the header: gas used must not
+// exceed the gas limit, and the gas limit must be non-zero.  The genesis
+// header is exempt, as it may carry special values.
+//
+// The returned error wraps ErrGasUsedExceedsLimit or ErrZeroGasLimit.
+func (h *Header) ValidateGas() error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	if h.IsGenesis() {
+		return nil
+	}
+	gasLimit, gasUsed := h.GasLimit(), h.GasUsed()
+	if gasLimit == 0 {
+		return invalidField("GasLimit", errors.Wrapf(ErrZeroGasLimit, "block %v", h.Number()))
+	}
+	if gasUsed > gasLimit {
+		return invalidField("GasUsed", errors.Wrapf(ErrGasUsedExceedsLimit,
+			"block %v: gasUsed=%d, gasLimit=%d", h.Number(), gasUsed, gasLimit))
+	}
+	return nil
+}
+
+// ValidateGasLimitDelta checks that the gas limit of the header differs from
+// that of the given parent by at most parent.GasLimit()/divisor, in either
+// direction, mirroring the Ethereum gas limit bound rule.  The genesis header
+// has no parent and passes regardless.
+//
+// The returned error wraps ErrGasLimitDeltaTooLarge.
+func (h *Header) ValidateGasLimitDelta(parent *Header, divisor uint64) error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	if h.IsGenesis() {
+		return nil
+	}
+	if isNilHeader(parent) {
+		return ErrHeaderIsNil
+	}
+	if divisor == 0 {
+		return errors.New("gas limit bound divisor is zero")
+	}
+	gasLimit, parentGasLimit := h.GasLimit(), parent.GasLimit()
+	delta := gasLimit - parentGasLimit
+	if gasLimit < parentGasLimit {
+		delta = parentGasLimit - gasLimit
+	}
+	if bound := parentGasLimit / divisor; delta > bound {
+		return invalidField("GasLimit", errors.Wrapf(ErrGasLimitDeltaTooLarge,
+			"block %v: gasLimit=%d, parent.GasLimit()=%d, bound=%d",
+			h.Number(), gasLimit, parentGasLimit, bound))
+	}
+	return nil
+}
+
+// ValidateShardID checks that the header belongs to one of the numShards
+// shards of the network, i.e. that its shard ID is below numShards.
+//
+// The returned error wraps ErrShardIDOutOfRange.
+func (h *Header) ValidateShardID(numShards uint32) error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	if shardID := h.ShardID(); shardID >= numShards {
+		return invalidField("ShardID", errors.Wrapf(ErrShardIDOutOfRange,
+			"block %v: shardID=%d, numShards=%d", h.Number(), shardID, numShards))
+	}
+	return nil
+}
+
+// ValidateTimestamp checks the header timestamp against the wall clock: it
+// must not be later than now plus maxDrift, and it must be nonzero unless the
+// header is the genesis header.
+//
+// The returned error wraps ErrTimestampTooFarAhead or ErrZeroTimestamp, or
+// ErrFieldUnset if the header has no timestamp.
+func (h *Header) ValidateTimestamp(now time.Time, maxDrift time.Duration) error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	timestamp := bigOrNil(h.Time)
+	if timestamp == nil {
+		return invalidField("Time", errors.Wrapf(ErrFieldUnset, "block %v", bigOrNil(h.Number)))
+	}
+	if timestamp.Sign() == 0 {
+		if h.IsGenesis() {
+			return nil
+		}
+		return invalidField("Time", errors.Wrapf(ErrZeroTimestamp, "block %v", h.Number()))
+	}
+	limit := now.Add(maxDrift).Unix()
+	if timestamp.Cmp(big.NewInt(limit)) > 0 {
+		return invalidField("Time", errors.Wrapf(ErrTimestampTooFarAhead,
+			"block %v: time=%v, limit=%d", h.Number(), timestamp, limit))
+	}
+	return nil
+}
+
+// ValidationOptions selects the checks run by Validate.  The zero value runs
+// the gas and extra data size checks with protocol defaults.
+type ValidationOptions struct {
+	// Parent, if not nil, is the parent header to validate against, as by
+	// ValidateAgainstParent.
+	Parent *Header
+	// ExpectedHash, if not nil, is the hash to verify, as by VerifyHash.
+	ExpectedHash *common.Hash
+	// MaxExtraSize is the extra data size limit; 0 means MaxExtraDataSize.
+	MaxExtraSize int
+	// ExemptGenesisExtra exempts the genesis header from the extra data size
+	// limit.
+	ExemptGenesisExtra bool
+	// SkipGas disables the ValidateGas check.
+	SkipGas bool
+	// SkipExtraSize disables the ValidateExtraSize check.
+	SkipExtraSize bool
+	// Aggregate makes Validate run all checks and return all failures as
+	// ValidationErrors, instead of stopping at the first failure.
+	Aggregate bool
+}
+
+// ValidationErrors is the error returned by Validate in aggregate mode when
+// more than one check fails, in check order.
+type ValidationErrors []error
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d header validation errors: %s",
+		len(errs), strings.Join(msgs, "; "))
+}
+
+// Validate runs the header checks selected by opts, in the order VerifyHash,
+// ValidateGas, ValidateExtraSize, and ValidateAgainstParent, and returns the
+// first error.  In aggregate mode, it runs all selected checks; a single
+// failure is returned as is, and multiple failures as ValidationErrors.
+func (h *Header) Validate(opts ValidationOptions) error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	var checks []func() error
+	if opts.ExpectedHash != nil {
+		checks = append(checks, func() error { return h.VerifyHash(*opts.ExpectedHash) })
+	}
+	if !opts.SkipGas {
+		checks = append(checks, h.ValidateGas)
+	}
+	if !opts.SkipExtraSize {
+		maxExtraSize := opts.MaxExtraSize
+		if maxExtraSize == 0 {
+			maxExtraSize = MaxExtraDataSize
+		}
+		checks = append(checks, func() error {
+			return h.ValidateExtraSize(maxExtraSize, opts.ExemptGenesisExtra)
+		})
+	}
+	if opts.Parent != nil {
+		checks = append(checks, func() error { return h.ValidateAgainstParent(opts.Parent) })
+	}
+	var errs ValidationErrors
+	for _, check := range checks {
+		if err := check(); err != nil {
+			if !opts.Aggregate {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
+
+// CompareRoots checks the transaction, receipt, and state trie roots declared
+// in the header against the given independently computed roots.  Each
+// mismatched root yields an error that wraps ErrRootMismatch and names the
+// root; a single mismatch is returned as is, and multiple ones as
+// ValidationErrors.
+func (h *Header) CompareRoots(txRoot, receiptRoot, stateRoot common.Hash) error {
+	if isNilHeader(h) {
+		return ErrHeaderIsNil
+	}
+	roots := []struct {
+		name               string
+		declared, computed common.Hash
+	}{
+		{"TxHash", h.TxHash(), txRoot},
+		{"ReceiptHash", h.ReceiptHash(), receiptRoot},
+		{"Root", h.Root(), stateRoot},
+	}
+	var errs ValidationErrors
+	for _, root := range roots {
+		if root.declared != root.computed {
+			errs = append(errs, invalidField(root.name, errors.Wrapf(ErrRootMismatch,
+				"declared %s, computed %s", root.declared.Hex(), root.computed.Hex())))
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
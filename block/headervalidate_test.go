@@ -0,0 +1,416 @@
+package block
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
+)
+
+func TestHeader_ValidateAgainstParent(t *testing.T) {
+	parent := (&Header{Header: v3.NewHeader()}).With().
+		ShardID(1).
+		Number(big.NewInt(100)).
+		Epoch(big.NewInt(5)).
+		Time(big.NewInt(1000)).
+		Header()
+	validChild := func() *Header {
+		return (&Header{Header: v3.NewHeader()}).With().
+			ParentHash(parent.Hash()).
+			ShardID(1).
+			Number(big.NewInt(101)).
+			Epoch(big.NewInt(5)).
+			Time(big.NewInt(1001)).
+			Header()
+	}
+	tests := []struct {
+		name   string
+		mutate func(h *Header)
+		want   error
+	}{
+		{"Valid", func(h *Header) {}, nil},
+		{"NextEpoch", func(h *Header) { h.SetEpoch(big.NewInt(6)) }, nil},
+		{"ParentHash", func(h *Header) { h.SetParentHash(common.Hash{1}) }, ErrParentHashMismatch},
+		{"NumberSame", func(h *Header) { h.SetNumber(big.NewInt(100)) }, ErrNumberNotSequential},
+		{"NumberSkip", func(h *Header) { h.SetNumber(big.NewInt(102)) }, ErrNumberNotSequential},
+		{"ShardID", func(h *Header) { h.SetShardID(2) }, ErrShardIDMismatch},
+		{"Epoch", func(h *Header) { h.SetEpoch(big.NewInt(4)) }, ErrEpochDecreased},
+		{"TimeSame", func(h *Header) { h.SetTime(big.NewInt(1000)) }, ErrTimeNotIncreasing},
+		{"TimeEarlier", func(h *Header) { h.SetTime(big.NewInt(999)) }, ErrTimeNotIncreasing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := validChild()
+			tt.mutate(h)
+			if err := h.ValidateAgainstParent(parent); errors.Cause(err) != tt.want {
+				t.Errorf("ValidateAgainstParent() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	t.Run("NilParent", func(t *testing.T) {
+		if err := validChild().ValidateAgainstParent(nil); err != ErrHeaderIsNil {
+			t.Errorf("ValidateAgainstParent() error = %v, want %v", err, ErrHeaderIsNil)
+		}
+	})
+}
+
+func TestHeader_ValidateViewID(t *testing.T) {
+	parent := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).Epoch(big.NewInt(5)).ViewID(big.NewInt(200)).Header()
+	tests := []struct {
+		name string
+		h    *Header
+		want error
+	}{
+		{"Increasing", parent.NewChild().ViewID(big.NewInt(201)).Header(), nil},
+		{"Equal", parent.NewChild().ViewID(big.NewInt(200)).Header(), nil},
+		{"Decreasing", parent.NewChild().ViewID(big.NewInt(199)).Header(), ErrViewIDDecreased},
+		{"NextEpoch", parent.NewChild().Epoch(big.NewInt(6)).ViewID(big.NewInt(1)).Header(), nil},
+		{"OtherVersion", (&Header{Header: v4.NewHeader()}).With().
+			Number(big.NewInt(101)).Epoch(big.NewInt(5)).ViewID(big.NewInt(1)).Header(), nil},
+		{"UnsupportedVersion", (&Header{Header: &customHeader{v3.NewHeader()}}).With().
+			Number(big.NewInt(101)).Epoch(big.NewInt(5)).ViewID(big.NewInt(1)).Header(), nil},
+		{"EpochUnset", (&Header{Header: &v3.Header{}}).With().
+			Number(big.NewInt(101)).ViewID(big.NewInt(201)).Header(), ErrFieldUnset},
+		{"ViewIDUnset", (&Header{Header: &v3.Header{}}).With().
+			Number(big.NewInt(101)).Epoch(big.NewInt(5)).Header(), ErrFieldUnset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.h.ValidateViewID(parent); errors.Cause(err) != tt.want {
+				t.Errorf("ValidateViewID() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	t.Run("NilParent", func(t *testing.T) {
+		if err := parent.ValidateViewID(nil); err != ErrHeaderIsNil {
+			t.Errorf("ValidateViewID() error = %v, want %v", err, ErrHeaderIsNil)
+		}
+	})
+}
+
+func TestValidateHeaderChain(t *testing.T) {
+	newChain := func(n int) []*Header {
+		headers := make([]*Header, n)
+		parentHash := common.Hash{}
+		for i := range headers {
+			headers[i] = (&Header{Header: v3.NewHeader()}).With().
+				ParentHash(parentHash).
+				ShardID(1).
+				Number(big.NewInt(int64(100 + i))).
+				Epoch(big.NewInt(5)).
+				Time(big.NewInt(int64(1000 + i))).
+				Header()
+			parentHash = headers[i].Hash()
+		}
+		return headers
+	}
+	// relink fixes up the parent hashes after headers[i] was modified.
+	relink := func(headers []*Header, i int) {
+		for ; i+1 < len(headers); i++ {
+			headers[i+1].SetParentHash(headers[i].Hash())
+		}
+	}
+	tests := []struct {
+		name   string
+		mutate func(headers []*Header)
+		want   error
+	}{
+		{"Valid", func([]*Header) {}, nil},
+		{"NumberGap", func(headers []*Header) {
+			for i := 2; i < len(headers); i++ {
+				headers[i].SetNumber(big.NewInt(int64(101 + i)))
+			}
+			relink(headers, 2)
+		}, ErrNumberNotSequential},
+		{"ParentHashMismatch", func(headers []*Header) {
+			headers[2].SetParentHash(common.Hash{1})
+		}, ErrParentHashMismatch},
+		{"ShardChange", func(headers []*Header) {
+			for i := 2; i < len(headers); i++ {
+				headers[i].SetShardID(2)
+			}
+			relink(headers, 2)
+		}, ErrShardIDMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := newChain(5)
+			tt.mutate(headers)
+			err := ValidateHeaderChain(headers)
+			if errors.Cause(err) != tt.want {
+				t.Fatalf("ValidateHeaderChain() error = %v, want %v", err, tt.want)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), "header #2: ") {
+				t.Errorf("ValidateHeaderChain() error = %q, want it to name header #2", err)
+			}
+		})
+	}
+	for _, headers := range [][]*Header{nil, newChain(1)} {
+		if err := ValidateHeaderChain(headers); err != nil {
+			t.Errorf("ValidateHeaderChain() of %d headers error = %v", len(headers), err)
+		}
+	}
+}
+
+func TestHeader_VerifyHash(t *testing.T) {
+	h := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(100)).
+		Extra([]byte("original")).
+		Header()
+	expected := h.Hash()
+	if err := h.VerifyHash(expected); err != nil {
+		t.Errorf("VerifyHash() error = %v, want nil", err)
+	}
+
+	// Tamper with the underlying header directly, bypassing the hash cache.
+	h.Header.SetExtra([]byte("tampered"))
+	err := h.VerifyHash(expected)
+	if errors.Cause(err) != ErrHashMismatch {
+		t.Fatalf("VerifyHash() error = %v, want %v", err, ErrHashMismatch)
+	}
+	if msg := err.Error(); !strings.Contains(msg, expected.Hex()) ||
+		!strings.Contains(msg, h.HashNoCache().Hex()) {
+		t.Errorf("VerifyHash() error %q does not name both hashes", msg)
+	}
+}
+
+func TestHeader_ValidateGas(t *testing.T) {
+	parent := common.HexToHash("0x1234")
+	tests := []struct {
+		name string
+		h    *Header
+		want error
+	}{
+		{"Valid", (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(10)).ParentHash(parent).GasLimit(1000).GasUsed(1000).Header(), nil},
+		{"GasUsedExceedsLimit", (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(10)).ParentHash(parent).GasLimit(1000).GasUsed(1001).Header(),
+			ErrGasUsedExceedsLimit},
+		{"ZeroGasLimit", (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(10)).ParentHash(parent).Header(), ErrZeroGasLimit},
+		{"Genesis", (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(0)).GasUsed(1).Header(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.h.ValidateGas(); errors.Cause(err) != tt.want {
+				t.Errorf("ValidateGas() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_ValidateGasLimitDelta(t *testing.T) {
+	parent := (&Header{Header: v3.NewHeader()}).With().
+		Number(big.NewInt(9)).GasLimit(1024000).Header()
+	child := func(gasLimit uint64) *Header {
+		return parent.NewChild().GasLimit(gasLimit).Header()
+	}
+	tests := []struct {
+		name string
+		h    *Header
+		want error
+	}{
+		{"Same", child(1024000), nil},
+		{"Increase", child(1024000 + 500), nil},
+		{"MaxIncrease", child(1024000 + 1000), nil},
+		{"MaxDecrease", child(1024000 - 1000), nil},
+		{"Jump", child(1024000 + 1001), ErrGasLimitDeltaTooLarge},
+		{"Drop", child(1024000 - 1001), ErrGasLimitDeltaTooLarge},
+		{"Genesis", (&Header{Header: v3.NewHeader()}).With().
+			Number(big.NewInt(0)).GasLimit(1).Header(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.h.ValidateGasLimitDelta(parent, 1024); errors.Cause(err) != tt.want {
+				t.Errorf("ValidateGasLimitDelta() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	t.Run("NilParent", func(t *testing.T) {
+		if err := child(1024000).ValidateGasLimitDelta(nil, 1024); err != ErrHeaderIsNil {
+			t.Errorf("ValidateGasLimitDelta() error = %v, want %v", err, ErrHeaderIsNil)
+		}
+	})
+}
+
+func TestHeader_ValidateShardID(t *testing.T) {
+	tests := []struct {
+		name    string
+		shardID uint32
+		want    error
+	}{
+		{"InRange", 3, nil},
+		{"EqualToNumShards", 4, ErrShardIDOutOfRange},
+		{"FarOutOfRange", 1000, ErrShardIDOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := (&Header{Header: v3.NewHeader()}).With().ShardID(tt.shardID).Header()
+			if err := h.ValidateShardID(4); errors.Cause(err) != tt.want {
+				t.Errorf("ValidateShardID(4) error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_ValidateTimestamp(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	maxDrift := 15 * time.Second
+	tests := []struct {
+		name   string
+		number int64
+		time   int64
+		want   error
+	}{
+		{"Valid", 10, now.Unix() - 5, nil},
+		{"AtDriftLimit", 10, now.Add(maxDrift).Unix(), nil},
+		{"BeyondDrift", 10, now.Add(maxDrift).Unix() + 1, ErrTimestampTooFarAhead},
+		{"ZeroNonGenesis", 10, 0, ErrZeroTimestamp},
+		{"ZeroGenesis", 0, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := (&Header{Header: v3.NewHeader()}).With().
+				Number(big.NewInt(tt.number)).
+				Time(big.NewInt(tt.time))
+			if tt.number != 0 {
+				s = s.ParentHash(common.HexToHash("0x1234"))
+			}
+			if err := s.Header().ValidateTimestamp(now, maxDrift); errors.Cause(err) != tt.want {
+				t.Errorf("ValidateTimestamp() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	t.Run("Unset", func(t *testing.T) {
+		h := (&Header{Header: &v3.Header{}}).With().Number(big.NewInt(10)).Header()
+		if err := h.ValidateTimestamp(now, maxDrift); errors.Cause(err) != ErrFieldUnset {
+			t.Errorf("ValidateTimestamp() error = %v, want %v", err, ErrFieldUnset)
+		}
+	})
+}
+
+func TestHeader_Validate(t *testing.T) {
+	parent := (&Header{Header: v3.NewHeader()}).With().
+		ParentHash(common.HexToHash("0x1234")).
+		Number(big.NewInt(100)).
+		Time(big.NewInt(1000)).
+		GasLimit(1000).
+		Header()
+	// Invalid in every checked aspect: wrong parent hash, oversized extra
+	// data, and gas used over the limit.
+	bad := (&Header{Header: v3.NewHeader()}).With().
+		ParentHash(common.HexToHash("0x5678")).
+		Number(big.NewInt(101)).
+		Time(big.NewInt(1001)).
+		GasLimit(1000).
+		GasUsed(2000).
+		UncheckedExtra(make([]byte, MaxExtraDataSize+1)).
+		Header()
+	good := (&Header{Header: v3.NewHeader()}).With().
+		ParentHash(parent.Hash()).
+		Number(big.NewInt(101)).
+		Time(big.NewInt(1001)).
+		GasLimit(1000).
+		GasUsed(500).
+		Header()
+	goodHash, badHash := good.Hash(), bad.Hash()
+	tests := []struct {
+		name string
+		h    *Header
+		opts ValidationOptions
+		want error
+	}{
+		{"Valid", good, ValidationOptions{Parent: parent, ExpectedHash: &goodHash}, nil},
+		{"Hash", bad, ValidationOptions{ExpectedHash: &goodHash}, ErrHashMismatch},
+		{"Gas", bad, ValidationOptions{}, ErrGasUsedExceedsLimit},
+		{"SkipGas", bad, ValidationOptions{SkipGas: true}, ErrExtraTooLarge},
+		{"MaxExtraSize", bad,
+			ValidationOptions{SkipGas: true, MaxExtraSize: MaxExtraDataSize + 1}, nil},
+		{"SkipExtraSize", bad, ValidationOptions{SkipGas: true, SkipExtraSize: true}, nil},
+		{"Parent", bad, ValidationOptions{
+			Parent: parent, ExpectedHash: &badHash, SkipGas: true, SkipExtraSize: true,
+		}, ErrParentHashMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.h.Validate(tt.opts); errors.Cause(err) != tt.want {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	t.Run("Aggregate", func(t *testing.T) {
+		err := bad.Validate(ValidationOptions{Parent: parent, Aggregate: true})
+		errs, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+		}
+		want := []error{ErrGasUsedExceedsLimit, ErrExtraTooLarge, ErrParentHashMismatch}
+		if len(errs) != len(want) {
+			t.Fatalf("Validate() returned %d errors, want %d: %v", len(errs), len(want), errs)
+		}
+		for i := range want {
+			if errors.Cause(errs[i]) != want[i] {
+				t.Errorf("error #%d = %v, want %v", i, errs[i], want[i])
+			}
+		}
+		if !strings.Contains(err.Error(), "3 header validation errors") {
+			t.Errorf("Error() = %q", err.Error())
+		}
+	})
+	t.Run("AggregateSingle", func(t *testing.T) {
+		err := bad.Validate(ValidationOptions{SkipExtraSize: true, Aggregate: true})
+		if errors.Cause(err) != ErrGasUsedExceedsLimit {
+			t.Errorf("Validate() error = %v, want %v", err, ErrGasUsedExceedsLimit)
+		}
+	})
+}
+
+func TestHeader_CompareRoots(t *testing.T) {
+	txRoot, receiptRoot, stateRoot :=
+		common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+	other := common.HexToHash("0xff")
+	h := (&Header{Header: v3.NewHeader()}).With().
+		TxHash(txRoot).ReceiptHash(receiptRoot).Root(stateRoot).Header()
+	tests := []struct {
+		name                  string
+		tx, receipt, state    common.Hash
+		wantMismatchedRootsIn []string
+	}{
+		{"AllMatch", txRoot, receiptRoot, stateRoot, nil},
+		{"TxHash", other, receiptRoot, stateRoot, []string{"TxHash"}},
+		{"ReceiptHash", txRoot, other, stateRoot, []string{"ReceiptHash"}},
+		{"Root", txRoot, receiptRoot, other, []string{"Root"}},
+		{"AllMismatched", other, other, other, []string{"TxHash", "ReceiptHash", "Root"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.CompareRoots(tt.tx, tt.receipt, tt.state)
+			var errs []error
+			switch e := err.(type) {
+			case nil:
+			case ValidationErrors:
+				errs = e
+			default:
+				errs = []error{e}
+			}
+			if len(errs) != len(tt.wantMismatchedRootsIn) {
+				t.Fatalf("CompareRoots() = %v, want %d mismatches", err, len(tt.wantMismatchedRootsIn))
+			}
+			for i, name := range tt.wantMismatchedRootsIn {
+				var fieldErr *HeaderValidationError
+				if errors.Cause(errs[i]) != ErrRootMismatch ||
+					!errors.As(errs[i], &fieldErr) || fieldErr.Field != name {
+					t.Errorf("mismatch #%d = %v, want %s %v", i, errs[i], name, ErrRootMismatch)
+				}
+			}
+		})
+	}
+}
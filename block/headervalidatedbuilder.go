@@ -0,0 +1,259 @@
+package block
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// ErrMandatoryHeaderFieldNotSet is the cause of the error returned by
+// ValidatedHeaderBuilder.Header when a mandatory field was not set.
+var ErrMandatoryHeaderFieldNotSet = errors.New("mandatory header field not set")
+
+// validatedField is a bit set of the mandatory fields set on a
+// ValidatedHeaderBuilder.
+type validatedField uint
+
+const (
+	validatedParentHash validatedField = 1 << iota
+	validatedNumber
+	validatedEpoch
+	validatedShardID
+)
+
+// mandatoryHeaderFields lists the fields that ValidatedHeaderBuilder requires
+// to be set explicitly, in reporting order.
+var mandatoryHeaderFields = []struct {
+	field validatedField
+	name  string
+}{
+	{validatedNumber, "Number"},
+	{validatedEpoch, "Epoch"},
+	{validatedShardID, "ShardID"},
+	{validatedParentHash, "ParentHash"},
+}
+
+// ValidatedHeaderBuilder is a header field setter that, unlike
+// HeaderFieldSetter, checks that all mandatory fields were explicitly set.
+//
+// See Header.WithValidated for how it is used.
+type ValidatedHeaderBuilder struct {
+	s   HeaderFieldSetter
+	set validatedField
+}
+
+// WithValidated returns a field setter context for the header, like With, but
+// its terminal Header call fails unless Number, Epoch, ShardID, and ParentHash
+// have all been set.  Example:
+//
+//	header, err := NewHeader(epoch).WithValidated().
+//		ParentHash(parent.Hash()).
+//		ShardID(parent.ShardID()).
+//		Number(new(big.Int).Add(parent.Number(), big.NewInt(1)).
+//		Epoch(epoch).
+//		Header()
+func (h *Header) WithValidated() ValidatedHeaderBuilder {
+	return ValidatedHeaderBuilder{s: h.With()}
+}
+
+// ParentHash sets the parent hash field.
+func (b ValidatedHeaderBuilder) ParentHash(newParentHash common.Hash) ValidatedHeaderBuilder {
+	b.s = b.s.ParentHash(newParentHash)
+	b.set |= validatedParentHash
+	return b
+}
+
+// Coinbase sets the coinbase address field.
+func (b ValidatedHeaderBuilder) Coinbase(newCoinbase common.Address) ValidatedHeaderBuilder {
+	b.s = b.s.Coinbase(newCoinbase)
+	return b
+}
+
+// Root sets the state trie root hash field.
+func (b ValidatedHeaderBuilder) Root(newRoot common.Hash) ValidatedHeaderBuilder {
+	b.s = b.s.Root(newRoot)
+	return b
+}
+
+// TxHash sets the transaction trie root hash field.
+func (b ValidatedHeaderBuilder) TxHash(newTxHash common.Hash) ValidatedHeaderBuilder {
+	b.s = b.s.TxHash(newTxHash)
+	return b
+}
+
+// ReceiptHash sets the same-shard transaction receipt trie hash.
+func (b ValidatedHeaderBuilder) ReceiptHash(newReceiptHash common.Hash) ValidatedHeaderBuilder {
+	b.s = b.s.ReceiptHash(newReceiptHash)
+	return b
+}
+
+// OutgoingReceiptHash sets the egress transaction receipt trie hash.
+func (b ValidatedHeaderBuilder) OutgoingReceiptHash(newOutgoingReceiptHash common.Hash) ValidatedHeaderBuilder {
+	b.s = b.s.OutgoingReceiptHash(newOutgoingReceiptHash)
+	return b
+}
+
+// IncomingReceiptHash sets the ingress transaction receipt trie hash.
+func (b ValidatedHeaderBuilder) IncomingReceiptHash(newIncomingReceiptHash common.Hash) ValidatedHeaderBuilder {
+	b.s = b.s.IncomingReceiptHash(newIncomingReceiptHash)
+	return b
+}
+
+// Bloom sets the smart contract log Bloom filter for this block.
+func (b ValidatedHeaderBuilder) Bloom(newBloom types.Bloom) ValidatedHeaderBuilder {
+	b.s = b.s.Bloom(newBloom)
+	return b
+}
+
+// Number sets the block number.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) Number(newNumber *big.Int) ValidatedHeaderBuilder {
+	b.s = b.s.Number(newNumber)
+	b.set |= validatedNumber
+	return b
+}
+
+// GasLimit sets the gas limit for transactions in this block.
+func (b ValidatedHeaderBuilder) GasLimit(newGasLimit uint64) ValidatedHeaderBuilder {
+	b.s = b.s.GasLimit(newGasLimit)
+	return b
+}
+
+// GasUsed sets the amount of gas used by transactions in this block.
+func (b ValidatedHeaderBuilder) GasUsed(newGasUsed uint64) ValidatedHeaderBuilder {
+	b.s = b.s.GasUsed(newGasUsed)
+	return b
+}
+
+// Time sets the UNIX timestamp of this block.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) Time(newTime *big.Int) ValidatedHeaderBuilder {
+	b.s = b.s.Time(newTime)
+	return b
+}
+
+// Extra sets the extra data field of this block.  Extra data longer than
+// MaxExtraDataSize makes Header fail.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) Extra(newExtra []byte) ValidatedHeaderBuilder {
+	b.s = b.s.Extra(newExtra)
+	return b
+}
+
+// MixDigest sets the mixhash of this block.
+func (b ValidatedHeaderBuilder) MixDigest(newMixDigest common.Hash) ValidatedHeaderBuilder {
+	b.s = b.s.MixDigest(newMixDigest)
+	return b
+}
+
+// ViewID sets the view ID in which the block was originally proposed.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) ViewID(newViewID *big.Int) ValidatedHeaderBuilder {
+	b.s = b.s.ViewID(newViewID)
+	return b
+}
+
+// Epoch sets the epoch number of this block.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) Epoch(newEpoch *big.Int) ValidatedHeaderBuilder {
+	b.s = b.s.Epoch(newEpoch)
+	b.set |= validatedEpoch
+	return b
+}
+
+// ShardID sets the shard ID to which this block belongs.
+func (b ValidatedHeaderBuilder) ShardID(newShardID uint32) ValidatedHeaderBuilder {
+	b.s = b.s.ShardID(newShardID)
+	b.set |= validatedShardID
+	return b
+}
+
+// LastCommitSignature sets the FBFT commit group signature for the last block.
+func (b ValidatedHeaderBuilder) LastCommitSignature(newLastCommitSignature [96]byte) ValidatedHeaderBuilder {
+	b.s = b.s.LastCommitSignature(newLastCommitSignature)
+	return b
+}
+
+// LastCommitBitmap sets the signatory bitmap of the previous block.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) LastCommitBitmap(newLastCommitBitmap []byte) ValidatedHeaderBuilder {
+	b.s = b.s.LastCommitBitmap(newLastCommitBitmap)
+	return b
+}
+
+// ShardStateHash sets the shard state hash.
+func (b ValidatedHeaderBuilder) ShardStateHash(newShardStateHash common.Hash) ValidatedHeaderBuilder {
+	b.s = b.s.ShardStateHash(newShardStateHash)
+	return b
+}
+
+// Vrf sets the output of the VRF for the epoch.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) Vrf(newVrf []byte) ValidatedHeaderBuilder {
+	b.s = b.s.Vrf(newVrf)
+	return b
+}
+
+// Vdf sets the output of the VDF for the epoch.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) Vdf(newVdf []byte) ValidatedHeaderBuilder {
+	b.s = b.s.Vdf(newVdf)
+	return b
+}
+
+// ShardState sets the RLP-encoded form of shard state
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) ShardState(newShardState []byte) ValidatedHeaderBuilder {
+	b.s = b.s.ShardState(newShardState)
+	return b
+}
+
+// CrossLinks sets the RLP-encoded form of non-beacon block headers chosen to be
+// canonical by the beacon committee.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) CrossLinks(newCrossLinks []byte) ValidatedHeaderBuilder {
+	b.s = b.s.CrossLinks(newCrossLinks)
+	return b
+}
+
+// BaseFee sets the base fee per gas of transactions in the block.  Header
+// versions before v4 do not carry a base fee and ignore nil.
+//
+// It stores a copy; the caller may freely modify the original.
+func (b ValidatedHeaderBuilder) BaseFee(newBaseFee *big.Int) ValidatedHeaderBuilder {
+	b.s = b.s.BaseFee(newBaseFee)
+	return b
+}
+
+// Header returns the header whose fields have been set, or an error naming
+// the mandatory fields that have not been set.  Call this at the end of a
+// field setter chain.
+//
+// Errors that HeaderFieldSetter.HeaderChecked reports, such as oversized
+// extra data, are returned as well.
+func (b ValidatedHeaderBuilder) Header() (*Header, error) {
+	var missing []string
+	for _, f := range mandatoryHeaderFields {
+		if b.set&f.field == 0 {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errors.Wrapf(ErrMandatoryHeaderFieldNotSet,
+			"missing %s", strings.Join(missing, ", "))
+	}
+	return b.s.HeaderChecked()
+}
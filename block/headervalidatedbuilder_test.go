@@ -0,0 +1,45 @@
+package block
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	v3 "github.com/harmony-one/harmony/block/v3"
+)
+
+func TestValidatedHeaderBuilder_Header(t *testing.T) {
+	h, err := (&Header{Header: v3.NewHeader()}).WithValidated().
+		Number(big.NewInt(10)).
+		Epoch(big.NewInt(2)).
+		ShardID(1).
+		Header()
+	if errors.Cause(err) != ErrMandatoryHeaderFieldNotSet {
+		t.Errorf("Header() error = %v, want %v", err, ErrMandatoryHeaderFieldNotSet)
+	}
+	if h != nil {
+		t.Errorf("Header() = %v, want nil", h)
+	}
+	if err != nil && !strings.Contains(err.Error(), "ParentHash") {
+		t.Errorf("Header() error %q does not name ParentHash", err)
+	}
+
+	h, err = (&Header{Header: v3.NewHeader()}).WithValidated().
+		ParentHash(common.HexToHash("0x1234")).
+		Number(big.NewInt(10)).
+		Epoch(big.NewInt(2)).
+		ShardID(1).
+		GasLimit(1000).
+		Header()
+	if err != nil {
+		t.Fatalf("Header() error = %v", err)
+	}
+	if h.ParentHash() != common.HexToHash("0x1234") ||
+		h.Number().Cmp(big.NewInt(10)) != 0 || h.Epoch().Cmp(big.NewInt(2)) != 0 ||
+		h.ShardID() != 1 || h.GasLimit() != 1000 {
+		t.Errorf("Header() = %v, fields not set as expected", h)
+	}
+}
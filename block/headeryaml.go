@@ -0,0 +1,58 @@
+package block
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// MarshalYAML returns the YAML form of the header, which mirrors its JSON
+// form: the same keys, including the version, with all numbers as strings so
+// that big values survive YAML integer handling.  Keys are sorted so that the
+// output is deterministic.
+func (h *Header) MarshalYAML() (interface{}, error) {
+	if isNilHeader(h) {
+		return nil, ErrHeaderIsNil
+	}
+	b, err := json.Marshal(newHeaderJSON(h))
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]*string
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, errors.Wrap(err, "cannot convert header JSON to YAML")
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	enc := make(yaml.MapSlice, 0, len(keys))
+	for _, key := range keys {
+		var value interface{}
+		if fields[key] != nil {
+			value = *fields[key]
+		}
+		enc = append(enc, yaml.MapItem{Key: key, Value: value})
+	}
+	return enc, nil
+}
+
+// UnmarshalYAML decodes the YAML form produced by MarshalYAML.  As with
+// UnmarshalJSON, the version field selects the concrete header type.
+func (h *Header) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if h == nil {
+		return ErrHeaderIsNil
+	}
+	var fields map[string]*string
+	if err := unmarshal(&fields); err != nil {
+		return err
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return errors.Wrap(err, "cannot convert header YAML to JSON")
+	}
+	return h.UnmarshalJSON(b)
+}
@@ -0,0 +1,71 @@
+package block
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v2"
+
+	blockif "github.com/harmony-one/harmony/block/interface"
+	v0 "github.com/harmony-one/harmony/block/v0"
+	v1 "github.com/harmony-one/harmony/block/v1"
+	v2 "github.com/harmony-one/harmony/block/v2"
+	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
+)
+
+func TestHeader_YAMLRoundTrip(t *testing.T) {
+	for _, hif := range []blockif.Header{
+		v0.NewHeader(), v1.NewHeader(), v2.NewHeader(), v3.NewHeader(),
+		v4.NewHeader(),
+	} {
+		t.Run(headerVersionName(hif), func(t *testing.T) {
+			bigNumber, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+			h := (&Header{Header: hif}).With().
+				ParentHash(common.HexToHash("0x1234")).
+				Number(bigNumber).
+				GasLimit(80000000).
+				Time(big.NewInt(1600000000)).
+				Extra([]byte("extra")).
+				ViewID(big.NewInt(105)).
+				Epoch(big.NewInt(7)).
+				ShardID(1).
+				LastCommitBitmap([]byte{0xff, 0x01}).
+				Header()
+			b, err := yaml.Marshal(h)
+			if err != nil {
+				t.Fatalf("MarshalYAML() error = %v", err)
+			}
+			again, err := yaml.Marshal(h)
+			if err != nil {
+				t.Fatalf("MarshalYAML() error = %v", err)
+			}
+			if !bytes.Equal(b, again) {
+				t.Error("MarshalYAML() is not deterministic")
+			}
+			var keys yaml.MapSlice
+			if err := yaml.Unmarshal(b, &keys); err != nil {
+				t.Fatalf("cannot parse MarshalYAML() output: %v", err)
+			}
+			if !sort.SliceIsSorted(keys, func(i, j int) bool {
+				return keys[i].Key.(string) < keys[j].Key.(string)
+			}) {
+				t.Errorf("MarshalYAML() keys are not sorted:\n%s", b)
+			}
+			got := &Header{}
+			if err := yaml.Unmarshal(b, got); err != nil {
+				t.Fatalf("UnmarshalYAML() error = %v", err)
+			}
+			if reflect.TypeOf(got.Header) != reflect.TypeOf(hif) {
+				t.Errorf("UnmarshalYAML() got type %T, want %T", got.Header, hif)
+			}
+			if got.Hash() != h.Hash() {
+				t.Errorf("UnmarshalYAML() got hash %x, want %x", got.Hash(), h.Hash())
+			}
+		})
+	}
+}
@@ -236,4 +236,14 @@ type Header interface {
 	// SetSlashes sets the RLP-encoded form of slashes
 	// It stores a copy; the caller may freely modify the original.
 	SetSlashes(newSlashes []byte)
+
+	// BaseFee is the base fee per gas of transactions in this block.
+	//
+	// The returned instance is a copy; the caller may do anything with it.
+	BaseFee() *big.Int
+
+	// SetBaseFee sets the base fee per gas of transactions in this block.
+	//
+	// It stores a copy; the caller may freely modify the original.
+	SetBaseFee(newBaseFee *big.Int)
 }
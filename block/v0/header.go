@@ -1,6 +1,7 @@
 package v0
 
 import (
+	"encoding/json"
 	"io"
 	"math/big"
 	"unsafe"
@@ -31,6 +32,12 @@ func (h *Header) DecodeRLP(s *rlp.Stream) error {
 	return s.Decode(&h.fields)
 }
 
+// MarshalFieldsJSON returns the JSON encoding of the header fields, keyed by
+// their JSON tags.
+func (h *Header) MarshalFieldsJSON() ([]byte, error) {
+	return json.Marshal(&h.fields)
+}
+
 // NewHeader creates a new header object.
 func NewHeader() *Header {
 	return &Header{headerFields{
@@ -406,6 +413,24 @@ type headerMarshaling struct {
 	Hash       common.Hash `json:"hash"` // adds call to Hash() in MarshalJSON
 }
 
+// BaseFee is the base fee per gas of transactions in this block, which V0
+// header does not carry; it always returns nil.
+func (h *Header) BaseFee() *big.Int {
+	return nil
+}
+
+// SetBaseFee sets the base fee per gas of transactions in this block, which
+// V0 header does not carry; setting nil, the only value it reports, is a
+// no-op.
+func (h *Header) SetBaseFee(newBaseFee *big.Int) {
+	if newBaseFee == nil {
+		return
+	}
+	h.Logger(utils.Logger()).Error().
+		Str("baseFee", newBaseFee.String()).
+		Msg("cannot store base fee in V0 header")
+}
+
 // Hash returns the block hash of the header, which is simply the keccak256 hash of its
 // RLP encoding.
 func (h *Header) Hash() common.Hash {
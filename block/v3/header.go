@@ -1,6 +1,7 @@
 package v3
 
 import (
+	"encoding/json"
 	"io"
 	"math/big"
 	"unsafe"
@@ -35,6 +36,12 @@ func (h *Header) DecodeRLP(s *rlp.Stream) error {
 	return s.Decode(&h.fields)
 }
 
+// MarshalFieldsJSON returns the JSON encoding of the header fields, keyed by
+// their JSON tags.
+func (h *Header) MarshalFieldsJSON() ([]byte, error) {
+	return json.Marshal(&h.fields)
+}
+
 // NewHeader creates a new header object.
 func NewHeader() *Header {
 	return &Header{headerFields{
@@ -69,8 +76,8 @@ type headerFields struct {
 	Vrf                 []byte   `json:"vrf"`
 	Vdf                 []byte   `json:"vdf"`
 	ShardState          []byte   `json:"shardState"`
-	CrossLinks          []byte   `json:"crossLink"`
-	Slashes             []byte   `json:slashes`
+	CrossLinks          []byte   `json:"crossLinks"`
+	Slashes             []byte   `json:"slashes"`
 }
 
 // ParentHash is the header hash of the parent block.  For the genesis block
@@ -380,6 +387,24 @@ func (h *Header) SetSlashes(newSlashes []byte) {
 	h.fields.Slashes = append(newSlashes[:0:0], newSlashes...)
 }
 
+// BaseFee is the base fee per gas of transactions in this block, which V3
+// header does not carry; it always returns nil.
+func (h *Header) BaseFee() *big.Int {
+	return nil
+}
+
+// SetBaseFee sets the base fee per gas of transactions in this block, which
+// V3 header does not carry; setting nil, the only value it reports, is a
+// no-op.
+func (h *Header) SetBaseFee(newBaseFee *big.Int) {
+	if newBaseFee == nil {
+		return
+	}
+	h.Logger(utils.Logger()).Error().
+		Str("baseFee", newBaseFee.String()).
+		Msg("cannot store base fee in V3 header")
+}
+
 // Hash returns the block hash of the header, which is simply the keccak256 hash of its
 // RLP encoding.
 func (h *Header) Hash() common.Hash {
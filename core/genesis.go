@@ -260,7 +260,7 @@ func (g *Genesis) ToBlock(db ethdb.Database) *types.Block {
 		ShardID(g.ShardID).
 		Time(new(big.Int).SetUint64(g.Timestamp)).
 		ParentHash(g.ParentHash).
-		Extra(g.ExtraData).
+		UncheckedExtra(g.ExtraData).
 		GasLimit(g.GasLimit).
 		GasUsed(g.GasUsed).
 		MixDigest(g.Mixhash).
@@ -0,0 +1,29 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+
+	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+)
+
+func TestGenesisToBlock(t *testing.T) {
+	for _, netType := range []nodeconfig.NetworkType{
+		nodeconfig.Mainnet, nodeconfig.Testnet, nodeconfig.Localnet,
+	} {
+		t.Run(string(netType), func(t *testing.T) {
+			spec := NewGenesisSpec(netType, 0)
+			// The default genesis extra data exceeds the block extra data limit,
+			// which does not apply to genesis blocks.
+			block := spec.ToBlock(rawdb.NewMemoryDatabase())
+			if block.NumberU64() != 0 {
+				t.Errorf("genesis block number = %d, want 0", block.NumberU64())
+			}
+			if !bytes.Equal(block.Extra(), spec.ExtraData) {
+				t.Errorf("genesis block extra = %q, want %q", block.Extra(), spec.ExtraData)
+			}
+		})
+	}
+}
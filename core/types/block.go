@@ -38,6 +38,7 @@ import (
 	v1 "github.com/harmony-one/harmony/block/v1"
 	v2 "github.com/harmony-one/harmony/block/v2"
 	v3 "github.com/harmony-one/harmony/block/v3"
+	v4 "github.com/harmony-one/harmony/block/v4"
 	"github.com/harmony-one/harmony/crypto/hash"
 	"github.com/harmony-one/harmony/internal/utils"
 	staking "github.com/harmony-one/harmony/staking/types"
@@ -143,7 +144,7 @@ type Body struct {
 func NewBodyForMatchingHeader(h *block.Header) (*Body, error) {
 	var bi BodyInterface
 	switch h.Header.(type) {
-	case *v3.Header:
+	case *v3.Header, *v4.Header:
 		bi = new(BodyV2)
 	case *v2.Header, *v1.Header:
 		bi = new(BodyV1)
@@ -341,26 +342,18 @@ func NewBlock(
 	}
 
 	// Put transactions into block
-	if len(txs) == 0 && len(stks) == 0 {
-		b.header.SetTxHash(EmptyRootHash)
-	} else {
+	b.header.SetTxHash(transactionsRoot(txs, stks))
+	if len(txs) != 0 || len(stks) != 0 {
 		b.transactions = make(Transactions, len(txs))
 		copy(b.transactions, txs)
 
 		b.stakingTransactions = make(staking.StakingTransactions, len(stks))
 		copy(b.stakingTransactions, stks)
-
-		b.header.SetTxHash(DeriveSha(
-			Transactions(txs),
-			staking.StakingTransactions(stks),
-		))
 	}
 
 	// Put receipts into block
-	if len(receipts) == 0 {
-		b.header.SetReceiptHash(EmptyRootHash)
-	} else {
-		b.header.SetReceiptHash(DeriveSha(Receipts(receipts)))
+	b.header.SetReceiptHash(receiptsRoot(receipts))
+	if len(receipts) != 0 {
 		b.header.SetBloom(CreateBloom(receipts))
 	}
 
@@ -378,6 +371,65 @@ func NewBlock(
 	return b
 }
 
+// transactionsRoot returns the transaction root NewBlock commits to for txs
+// followed by stks.
+func transactionsRoot(txs []*Transaction, stks []*staking.StakingTransaction) common.Hash {
+	if len(txs) == 0 && len(stks) == 0 {
+		return EmptyRootHash
+	}
+	return DeriveSha(Transactions(txs), staking.StakingTransactions(stks))
+}
+
+// VerifyTransactionRoot checks that the transaction root declared in header
+// is the one NewBlock computes from txs and stks, e.g.
+//
+//	VerifyTransactionRoot(b.Header(), b.Transactions(), b.StakingTransactions())
+//
+// The returned error wraps block.ErrRootMismatch.
+func VerifyTransactionRoot(
+	header *block.Header, txs []*Transaction, stks []*staking.StakingTransaction,
+) error {
+	if header == nil || header.Header == nil {
+		return block.ErrHeaderIsNil
+	}
+	if declared, computed := header.TxHash(), transactionsRoot(txs, stks); declared != computed {
+		return &block.HeaderValidationError{
+			Field: "TxHash",
+			Err: errors.Wrapf(block.ErrRootMismatch,
+				"declared %s, computed %s from %d transactions and %d staking transactions",
+				declared.Hex(), computed.Hex(), len(txs), len(stks)),
+		}
+	}
+	return nil
+}
+
+// receiptsRoot returns the receipt root NewBlock commits to for receipts.
+func receiptsRoot(receipts []*Receipt) common.Hash {
+	if len(receipts) == 0 {
+		return EmptyRootHash
+	}
+	return DeriveSha(Receipts(receipts))
+}
+
+// VerifyReceiptRoot checks that the receipt root declared in header is the
+// one NewBlock computes from receipts.
+//
+// The returned error wraps block.ErrRootMismatch.
+func VerifyReceiptRoot(header *block.Header, receipts []*Receipt) error {
+	if header == nil || header.Header == nil {
+		return block.ErrHeaderIsNil
+	}
+	if declared, computed := header.ReceiptHash(), receiptsRoot(receipts); declared != computed {
+		return &block.HeaderValidationError{
+			Field: "ReceiptHash",
+			Err: errors.Wrapf(block.ErrRootMismatch,
+				"declared %s, computed %s from %d receipts",
+				declared.Hex(), computed.Hex(), len(receipts)),
+		}
+	}
+	return nil
+}
+
 // NewBlockWithHeader creates a block with the given header data. The
 // header data is copied, changes to header and to the field values
 // will not affect the block.
@@ -388,12 +440,7 @@ func NewBlockWithHeader(header *block.Header) *Block {
 // CopyHeader creates a deep copy of a block header to prevent side effects from
 // modifying a header variable.
 func CopyHeader(h *block.Header) *block.Header {
-	if h == nil {
-		return nil
-	}
-	cpy := *h
-	cpy.Header = cpy.Header.Copy()
-	return &cpy
+	return h.Copy()
 }
 
 // DecodeRLP decodes the Ethereum
@@ -423,7 +470,7 @@ func (b *Block) EncodeRLP(w io.Writer) error {
 	var eb interface{}
 
 	switch h := b.header.Header.(type) {
-	case *v3.Header:
+	case *v3.Header, *v4.Header:
 		eb = extblockV2{b.header, b.transactions, b.stakingTransactions, b.uncles, b.incomingReceipts}
 	case *v2.Header, *v1.Header:
 		eb = extblockV1{b.header, b.transactions, b.uncles, b.incomingReceipts}
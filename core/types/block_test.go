@@ -19,11 +19,14 @@ package types
 
 import (
 	"bytes"
+	"math/big"
 	"reflect"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/taggedrlp"
+	"github.com/pkg/errors"
 
 	"github.com/harmony-one/harmony/block"
 	blockfactory "github.com/harmony-one/harmony/block/factory"
@@ -31,6 +34,7 @@ import (
 	v0 "github.com/harmony-one/harmony/block/v0"
 	v1 "github.com/harmony-one/harmony/block/v1"
 	v2 "github.com/harmony-one/harmony/block/v2"
+	staking "github.com/harmony-one/harmony/staking/types"
 )
 
 var (
@@ -927,7 +931,7 @@ func TestBlock_EncodeRLP(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			b := &Block{header: &block.Header{tt.header}}
+			b := &Block{header: &block.Header{Header: tt.header}}
 			w := &bytes.Buffer{}
 			err := b.EncodeRLP(w)
 			if (err != nil) != tt.wantErr {
@@ -1065,3 +1069,68 @@ func TestBody_DecodeRLP(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyReceiptRoot(t *testing.T) {
+	receipts := []*Receipt{
+		NewReceipt(nil, false, 21000),
+		NewReceipt(nil, true, 42000),
+	}
+	h := blockfactory.NewTestHeader().With().ReceiptHash(DeriveSha(Receipts(receipts))).Header()
+	if err := VerifyReceiptRoot(h, receipts); err != nil {
+		t.Errorf("VerifyReceiptRoot() error = %v", err)
+	}
+	if err := VerifyReceiptRoot(h, receipts[:1]); errors.Cause(err) != block.ErrRootMismatch {
+		t.Errorf("VerifyReceiptRoot() of a subset error = %v, want %v", err, block.ErrRootMismatch)
+	}
+	if err := VerifyReceiptRoot(h, nil); errors.Cause(err) != block.ErrRootMismatch {
+		t.Errorf("VerifyReceiptRoot() of no receipts error = %v, want %v", err, block.ErrRootMismatch)
+	}
+	if got, want := receiptsRoot(nil), DeriveSha(Receipts{}); got != want || got != EmptyRootHash {
+		t.Errorf("receiptsRoot(nil) = %s, want %s (DeriveSha) and %s (EmptyRootHash)",
+			got.Hex(), want.Hex(), EmptyRootHash.Hex())
+	}
+	empty := blockfactory.NewTestHeader().With().ReceiptHash(EmptyRootHash).Header()
+	if err := VerifyReceiptRoot(empty, nil); err != nil {
+		t.Errorf("VerifyReceiptRoot() of empty header error = %v", err)
+	}
+}
+
+func TestVerifyTransactionRoot(t *testing.T) {
+	txs := []*Transaction{
+		NewTransaction(0, common.Address{1}, 0, big.NewInt(1), 21000, big.NewInt(1), nil),
+		NewTransaction(1, common.Address{2}, 0, big.NewInt(2), 21000, big.NewInt(1), nil),
+	}
+	receipts := []*Receipt{
+		NewReceipt(nil, false, 21000),
+		NewReceipt(nil, false, 21000),
+	}
+	b := NewBlock(blockfactory.NewTestHeader(), txs, receipts, nil, nil, nil)
+	if err := VerifyTransactionRoot(b.Header(), b.Transactions(), b.StakingTransactions()); err != nil {
+		t.Errorf("VerifyTransactionRoot() error = %v", err)
+	}
+	if err := VerifyReceiptRoot(b.Header(), receipts); err != nil {
+		t.Errorf("VerifyReceiptRoot() error = %v", err)
+	}
+	reordered := []*Transaction{txs[1], txs[0]}
+	if err := VerifyTransactionRoot(b.Header(), reordered, nil); errors.Cause(err) != block.ErrRootMismatch {
+		t.Errorf("VerifyTransactionRoot() of reordered transactions error = %v, want %v",
+			err, block.ErrRootMismatch)
+	}
+	if err := VerifyTransactionRoot(b.Header(), nil, nil); errors.Cause(err) != block.ErrRootMismatch {
+		t.Errorf("VerifyTransactionRoot() of no transactions error = %v, want %v",
+			err, block.ErrRootMismatch)
+	}
+
+	empty := NewBlock(blockfactory.NewTestHeader(), nil, nil, nil, nil, nil)
+	if err := VerifyTransactionRoot(empty.Header(), nil, nil); err != nil {
+		t.Errorf("VerifyTransactionRoot() of empty block error = %v", err)
+	}
+	if err := VerifyTransactionRoot(
+		empty.Header(), Transactions{}, staking.StakingTransactions{},
+	); err != nil {
+		t.Errorf("VerifyTransactionRoot() of empty lists error = %v", err)
+	}
+	if got := empty.Header().TxHash(); got != DeriveSha(Transactions{}) {
+		t.Errorf("empty block TxHash = %s, want %s", got.Hex(), DeriveSha(Transactions{}).Hex())
+	}
+}
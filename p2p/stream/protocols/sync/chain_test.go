@@ -10,7 +10,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 	protobuf "github.com/golang/protobuf/proto"
-	"github.com/harmony-one/harmony/block"
 	"github.com/harmony-one/harmony/core/types"
 	syncpb "github.com/harmony-one/harmony/p2p/stream/protocols/sync/message"
 )
@@ -87,7 +86,7 @@ func checkBlocksResult(bns []uint64, b []byte) error {
 func makeTestBlock(bn uint64) *types.Block {
 	header := testHeader.Copy()
 	header.SetNumber(big.NewInt(int64(bn)))
-	return types.NewBlockWithHeader(&block.Header{Header: header})
+	return types.NewBlockWithHeader(header)
 }
 
 func decodeBlocksBytes(bbs [][]byte) ([]*types.Block, error) {